@@ -16,18 +16,23 @@ type AIModelIface interface {
 	Architecture() Architecture
 	Sources() []string
 	Warnings() []Warning
+	Task() Task
+	Modalities() Modalities
 }
 
 var _ AIModelIface = &AIModel{}
 
 // AIModel represents an AI model with its properties.
 type AIModel struct {
-	name             string
-	provider         Provider
-	architecture     Architecture
-	warnings         []Warning
-	sources          []string
-	quantizationBits float64
+	name           string
+	provider       Provider
+	architecture   Architecture
+	warnings       []Warning
+	sources        []string
+	precision      Precision
+	task           Task
+	modalities     Modalities
+	catalogVersion CatalogVersion
 }
 
 // Provider is the name of the provider, eg "openai", "anthropic".
@@ -44,19 +49,26 @@ const (
 
 // Warning represents a warning message associated with the model.
 type Warning struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	Severity Severity `json:"severity,omitempty"`
 }
 
 // Alias is a model alias, used to map different names to the same model.
+// ModelName is the alternative name and ModelAlias is the name it resolves to, which is either a
+// catalog model or another alias. An empty ProviderName makes the alias apply to every provider.
+// Aliases also describe deployments of a model under another name; a non-empty Precision overrides
+// the precision of the model for that deployment.
 type Alias struct {
-	ProviderName string `json:"provider"`
-	ModelName    string `json:"name"`
-	ModelAlias   string `json:"alias"`
+	ProviderName string    `json:"provider"`
+	ModelName    string    `json:"name"`
+	ModelAlias   string    `json:"alias"`
+	Precision    Precision `json:"precision,omitempty"`
 }
 
 // ModelData is the top-level structure for the model used in the data file.
 type ModelData struct {
+	Version string    `json:"version,omitempty"`
 	Aliases []Alias   `json:"aliases,omitempty"`
 	Models  []AIModel `json:"models"`
 }
@@ -84,27 +96,23 @@ type Parameters struct {
 }
 
 // NewAIModel creates a new AIModel instance based on the provided name.
+// Snapshot names that are not in the catalog are matched as described in Registry.Match, in which case
+// the model carries a warning. It returns an error wrapping ErrModelNotFound when nothing matches.
 func NewAIModel(name string) (*AIModel, error) {
 	if name == "" {
 		return nil, errors.New("name cannot be empty")
 	}
 
-	source := "aimodel/data/aimodels.json"
-	models, err := FetchAIModels(source)
+	models, err := DefaultRegistry()
 	if err != nil {
-		slog.Error("failed to fetch AI models", "err", err)
+		slog.Error("failed to load AI models", "error", err)
 		return nil, err
 	}
-	modelsMap, err := CreateModelsMap(models)
+	match, err := models.Match("", name)
 	if err != nil {
-		slog.Error("failed to map AI models", "error", err)
 		return nil, err
 	}
-	model := modelsMap[name]
-	if model.quantizationBits == 0 {
-		model.quantizationBits = 8
-	}
-	return &model, nil
+	return match.Model, nil
 }
 
 func (a *AIModel) Provider() Provider {
@@ -127,32 +135,100 @@ func (a *AIModel) Warnings() []Warning {
 	return a.warnings
 }
 
-// ModelRequiredMemory returns the required memory to load the model on a GPUModel.
+// Task returns the kind of request the model serves. Catalog entries without a task are chat models.
+func (a *AIModel) Task() Task {
+	if a.task == "" {
+		return TaskChat
+	}
+	return a.task
+}
+
+// Modalities returns the kinds of data the model consumes and produces, defaulting to those of its task.
+func (a *AIModel) Modalities() Modalities {
+	if len(a.modalities.Input) == 0 && len(a.modalities.Output) == 0 {
+		return DefaultModalities(a.Task())
+	}
+	return a.modalities
+}
+
+// LowConfidence reports whether the model carries a warning that lowers the confidence of its estimates.
+func (a *AIModel) LowConfidence() bool {
+	return LowConfidence(a.warnings)
+}
+
+// CatalogVersion returns the version of the catalog the model was loaded from. It is empty for models
+// registered with Registry.Register.
+func (a *AIModel) CatalogVersion() CatalogVersion {
+	return a.catalogVersion
+}
+
+// Precision returns the precision the model is served in, or DefaultPrecision when unknown.
+func (a *AIModel) Precision() Precision {
+	if a.precision == "" {
+		return DefaultPrecision
+	}
+	return a.precision
+}
+
+// WithPrecision returns a copy of the model served in the given precision, eg to describe a self-hosted
+// quantized deployment for a single request.
+func (a *AIModel) WithPrecision(precision Precision) (*AIModel, error) {
+	if _, err := precision.Bits(); err != nil {
+		return nil, err
+	}
+	model := *a
+	model.precision = precision
+	return &model, nil
+}
+
+// ModelRequiredMemory returns the required memory in GB to load the model on a GPUModel, including a 20%
+// overhead for activations and caches.
 func (a *AIModel) ModelRequiredMemory() float64 {
-	return 1.2 * a.architecture.Parameters.Total.Max * a.quantizationBits / 8
+	bits, err := a.Precision().Bits()
+	if err != nil {
+		bits, _ = DefaultPrecision.Bits()
+	}
+	return 1.2 * a.architecture.Parameters.Total.Max * bits / 8
 }
 
-// FetchAIModels parses and normalizes unstructured json into a list of AIModel objects.
+// FetchAIModels reads an external catalog file and parses it into a list of AIModel objects.
+// Files with a .yaml or .yml extension are parsed as YAML, any other file as JSON.
+// The default catalog is embedded in the binary; use LoadAIModels to add an external catalog on top of it.
 func FetchAIModels(source string) (*ModelData, error) {
 	data, err := os.ReadFile(source)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	if isYAML(source) {
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parseAIModels(data)
+}
 
+// parseAIModels parses and normalizes unstructured json into a list of AIModel objects.
+func parseAIModels(data []byte) (*ModelData, error) {
 	var p fastjson.Parser
 	v, err := p.ParseBytes(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
-	models := &ModelData{}
+	models := &ModelData{Version: string(v.GetStringBytes("version"))}
 	aliases := v.GetArray("aliases")
 	for _, alias := range aliases {
+		precision, err := ParsePrecision(string(alias.GetStringBytes("precision")))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse precision of alias %q: %w", alias.GetStringBytes("name"), err)
+		}
 		models.Aliases = append(
 			models.Aliases, Alias{
 				ProviderName: string(alias.GetStringBytes("provider")),
 				ModelName:    string(alias.GetStringBytes("name")),
 				ModelAlias:   string(alias.GetStringBytes("alias")),
+				Precision:    precision,
 			},
 		)
 	}
@@ -165,45 +241,155 @@ func FetchAIModels(source string) (*ModelData, error) {
 			sources:  parseStringArray(model.GetArray("sources")),
 			warnings: parseWarnings(model.GetArray("warnings")),
 		}
+		precision, err := ParsePrecision(string(model.GetStringBytes("precision")))
+		if err != nil {
+			slog.Error("failed to parse precision", "error", err, "model-name", aiModel.name)
+			return nil, fmt.Errorf("failed to parse precision of model %q: %w", aiModel.name, err)
+		}
+		aiModel.precision = precision
+
+		task, err := ParseTask(string(model.GetStringBytes("task")))
+		if err != nil {
+			slog.Error("failed to parse task", "error", err, "model-name", aiModel.name)
+			return nil, fmt.Errorf("failed to parse task of model %q: %w", aiModel.name, err)
+		}
+		aiModel.task = task
+		modalities, err := parseModalities(model.Get("modalities"))
+		if err != nil {
+			slog.Error("failed to parse modalities", "error", err, "model-name", aiModel.name)
+			return nil, fmt.Errorf("failed to parse modalities of model %q: %w", aiModel.name, err)
+		}
+		aiModel.modalities = modalities
 
 		architecture := model.Get("architecture")
 		if architecture != nil {
 			aiModel.architecture.Type = ArchitectureType(architecture.GetStringBytes("type"))
-			parameters := architecture.Get("parameters")
-
-			parsedParams := Parameters{}
-			if parameters.Exists("total") {
-				totalVal, err := parseRangeValue(parameters.Get("total"))
-				if err != nil {
-					slog.Error("failed to parse total value", "error", err, "model-name", aiModel.name)
-					return nil, fmt.Errorf("failed to parse min value for total: %w", err)
-				}
-				parsedParams.Total = totalVal
-			}
-			if parameters.Exists("active") {
-				activeVal, err := parseRangeValue(parameters.Get("active"))
-				if err != nil {
-					slog.Error("failed to parse active value", "error", err, "model-name", aiModel.name)
-					return nil, fmt.Errorf("failed to parse max value for active: %w", err)
-				}
-				parsedParams.Active = activeVal
-			}
-			if parameters.Exists("min") || parameters.Exists("max") {
-				totalVal, err := parseRangeValue(parameters)
-				if err != nil {
-					slog.Error("failed to parse", "error", err, "model-name", aiModel.name)
-					return nil, fmt.Errorf("failed to parse max value for active: %w", err)
-				}
-				parsedParams.Total = totalVal
+			parsedParams, err := parseParameters(aiModel.architecture.Type, architecture.Get("parameters"))
+			if err != nil {
+				slog.Error("failed to parse parameters", "error", err, "model-name", aiModel.name)
+				return nil, err
 			}
 			aiModel.architecture.Parameters = parsedParams
 		}
+		if err := validateArchitecture(aiModel.architecture); err != nil {
+			slog.Error("invalid architecture", "error", err, "model-name", aiModel.name)
+			return nil, fmt.Errorf("invalid architecture for model %q: %w", aiModel.name, err)
+		}
 		models.Models = append(models.Models, aiModel)
 	}
 
+	catalogVersion := models.CatalogVersion()
+	for i := range models.Models {
+		models.Models[i].catalogVersion = catalogVersion
+	}
 	return models, nil
 }
 
+// parseParameters parses the parameter counts of an architecture. The catalog lists dense models either
+// with a scalar, a {"min", "max"} range or {"total", "active"}, and MoE models with {"total", "active"}.
+func parseParameters(architectureType ArchitectureType, parameters *fastjson.Value) (Parameters, error) {
+	parsedParams := Parameters{}
+	if parameters == nil {
+		return parsedParams, nil
+	}
+
+	if parameters.Type() != fastjson.TypeNumber && parameters.Type() != fastjson.TypeObject {
+		return Parameters{}, fmt.Errorf("failed to parse parameters: unexpected type: %s", parameters.Type())
+	}
+	if parameters.Type() == fastjson.TypeNumber || parameters.Exists("min") || parameters.Exists("max") {
+		totalVal, err := parseRangeValue(parameters)
+		if err != nil {
+			return Parameters{}, fmt.Errorf("failed to parse parameters: %w", err)
+		}
+		parsedParams.Total = totalVal
+	}
+	if parameters.Exists("total") {
+		totalVal, err := parseRangeValue(parameters.Get("total"))
+		if err != nil {
+			return Parameters{}, fmt.Errorf("failed to parse total value: %w", err)
+		}
+		parsedParams.Total = totalVal
+	}
+	if parameters.Exists("active") {
+		activeVal, err := parseRangeValue(parameters.Get("active"))
+		if err != nil {
+			return Parameters{}, fmt.Errorf("failed to parse active value: %w", err)
+		}
+		parsedParams.Active = activeVal
+	}
+
+	// Dense models use every parameter for each token, so the catalog only lists the total.
+	if architectureType == DENSE && parsedParams.Active == (common.RangeValue{}) {
+		parsedParams.Active = parsedParams.Total
+	}
+	return parsedParams, nil
+}
+
+// validateArchitecture checks that the architecture carries consistent parameters needed to compute impacts.
+func validateArchitecture(architecture Architecture) error {
+	switch architecture.Type {
+	case DENSE, MOE:
+	case "":
+		return errors.New("architecture type is missing")
+	default:
+		return fmt.Errorf("unknown architecture type %q", architecture.Type)
+	}
+	if architecture.Parameters.Total.Max <= 0 {
+		return errors.New("total parameters must be greater than 0")
+	}
+	if architecture.Parameters.Active.Max <= 0 {
+		return fmt.Errorf("%s model is missing active parameters", architecture.Type)
+	}
+	if err := validateRange(architecture.Parameters.Total); err != nil {
+		return fmt.Errorf("invalid total parameters: %w", err)
+	}
+	if err := validateRange(architecture.Parameters.Active); err != nil {
+		return fmt.Errorf("invalid active parameters: %w", err)
+	}
+	if architecture.Parameters.Active.Max > architecture.Parameters.Total.Max {
+		return fmt.Errorf("active parameters (%g) exceed total parameters (%g)",
+			architecture.Parameters.Active.Max, architecture.Parameters.Total.Max)
+	}
+	return nil
+}
+
+func validateRange(value common.RangeValue) error {
+	if value.Min < 0 {
+		return fmt.Errorf("min (%g) cannot be negative", value.Min)
+	}
+	if value.Min > value.Max {
+		return fmt.Errorf("min (%g) is greater than max (%g)", value.Min, value.Max)
+	}
+	return nil
+}
+
+// parseModalities parses {"input": [...], "output": [...]}. A missing value leaves the modalities empty so
+// that the defaults of the model's task apply.
+func parseModalities(value *fastjson.Value) (Modalities, error) {
+	if value == nil {
+		return Modalities{}, nil
+	}
+	if value.Type() != fastjson.TypeObject {
+		return Modalities{}, fmt.Errorf("unexpected type: %s", value.Type())
+	}
+	var modalities Modalities
+	for _, v := range value.GetArray("input") {
+		modality, err := ParseModality(string(v.GetStringBytes()))
+		if err != nil {
+			return Modalities{}, fmt.Errorf("invalid input modality: %w", err)
+		}
+		modalities.Input = append(modalities.Input, modality)
+	}
+	for _, v := range value.GetArray("output") {
+		modality, err := ParseModality(string(v.GetStringBytes()))
+		if err != nil {
+			return Modalities{}, fmt.Errorf("invalid output modality: %w", err)
+		}
+		modalities.Output = append(modalities.Output, modality)
+	}
+	return modalities, nil
+}
+
 func parseStringArray(arr []*fastjson.Value) []string {
 	result := make([]string, 0)
 	for _, v := range arr {
@@ -212,15 +398,30 @@ func parseStringArray(arr []*fastjson.Value) []string {
 	return result
 }
 
+// parseWarnings parses warnings given either as bare codes, eg "model-arch-multimodal", or as objects with
+// a code, message and optional severity. Missing messages and severities are taken from the known codes.
 func parseWarnings(arr []*fastjson.Value) []Warning {
 	warnings := make([]Warning, 0)
 	for _, v := range arr {
-		warnings = append(
-			warnings, Warning{
-				Code:    string(v.GetStringBytes("code")),
-				Message: string(v.GetStringBytes("message")),
-			},
-		)
+		if v.Type() == fastjson.TypeString {
+			warnings = append(warnings, NewWarning(string(v.GetStringBytes())))
+			continue
+		}
+
+		warning := Warning{
+			Code:     string(v.GetStringBytes("code")),
+			Message:  string(v.GetStringBytes("message")),
+			Severity: Severity(v.GetStringBytes("severity")),
+		}
+		if definition, ok := LookupWarning(warning.Code); ok {
+			if warning.Message == "" {
+				warning.Message = definition.Message
+			}
+			if warning.Severity == "" {
+				warning.Severity = definition.Severity
+			}
+		}
+		warnings = append(warnings, warning)
 	}
 	return warnings
 }
@@ -270,6 +471,9 @@ func parseRangeValue(value *fastjson.Value) (common.RangeValue, error) {
 	}
 }
 
+// CreateModelsMap indexes models by name.
+//
+// Deprecated: use NewRegistry, which also indexes by provider and supports filtering.
 func CreateModelsMap(models *ModelData) (map[string]AIModel, error) {
 	modelsMap := make(map[string]AIModel)
 	for _, model := range models.Models {
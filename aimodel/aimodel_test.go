@@ -79,6 +79,34 @@ func TestParseWarnings(t *testing.T) {
 				{Code: "warning-2", Message: "Another warning"},
 			},
 		},
+		{
+			name:      "String warnings array",
+			jsonInput: `["model-arch-multimodal", "unknown-code"]`,
+			expected: []Warning{
+				{
+					Code:     "model-arch-multimodal",
+					Message:  "The model architecture is multimodal, expect lower precision.",
+					Severity: SeverityLowConfidence,
+				},
+				{Code: "unknown-code", Message: "unknown-code"},
+			},
+		},
+		{
+			name:      "Object warning with known code and no message",
+			jsonInput: `[{"code": "model-arch-not-released"}]`,
+			expected: []Warning{
+				{
+					Code:     "model-arch-not-released",
+					Message:  "The model architecture has not been released, expect lower precision.",
+					Severity: SeverityLowConfidence,
+				},
+			},
+		},
+		{
+			name:      "Object warning with severity",
+			jsonInput: `[{"code": "custom", "message": "Custom warning", "severity": "info"}]`,
+			expected:  []Warning{{Code: "custom", Message: "Custom warning", Severity: SeverityInfo}},
+		},
 		{
 			name:      "Empty warnings array",
 			jsonInput: `[]`,
@@ -274,6 +302,24 @@ func TestFetchAIModels(t *testing.T) {
 			}`,
 			expectError: true,
 		},
+		{
+			name: "returns error when moe model is missing active parameters",
+			jsonContent: `{
+				"models": [
+					{
+						"provider": "openai",
+						"name": "gpt-4",
+						"architecture": {
+							"type": "moe",
+							"parameters": {
+								"total": 1760.8
+							}
+						}
+					}
+				]
+			}`,
+			expectError: true,
+		},
 		{
 			name: "returns unexpected type error when range min value is unsupported",
 			jsonContent: `{
@@ -339,3 +385,391 @@ func TestFetchAIModels(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAIModel(t *testing.T) {
+	tests := []struct {
+		name          string
+		modelName     string
+		expectError   bool
+		expectedModel *AIModel
+	}{
+		{
+			name:      "returns model from the embedded catalog",
+			modelName: "gpt-4",
+			expectedModel: &AIModel{
+				name:     "gpt-4",
+				provider: OpenAI,
+				architecture: Architecture{
+					Type: MOE,
+					Parameters: Parameters{
+						Total:  common.RangeValue{Min: 1760, Max: 1760},
+						Active: common.RangeValue{Min: 220, Max: 880},
+					},
+				},
+			},
+		},
+		{
+			name:        "returns error for empty name",
+			modelName:   "",
+			expectError: true,
+		},
+		{
+			name:        "returns error for unknown name",
+			modelName:   "not-a-model",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := NewAIModel(tt.modelName)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedModel.Name(), model.Name())
+			assert.Equal(t, tt.expectedModel.Provider(), model.Provider())
+			assert.Equal(t, tt.expectedModel.Architecture(), model.Architecture())
+		})
+	}
+}
+
+func TestLoadAIModels(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "aimodels_test_*.json")
+	assert.NoError(t, err)
+	defer os.Remove(tempFile.Name())
+
+	_, err = tempFile.WriteString(`{
+		"models": [
+			{
+				"provider": "openai",
+				"name": "external-model",
+				"architecture": {"type": "dense", "parameters": 7}
+			}
+		]
+	}`)
+	assert.NoError(t, err)
+
+	// Load into a copy of the embedded catalog rather than the default registry, which other tests share.
+	data, err := fetchEmbeddedAIModels()
+	assert.NoError(t, err)
+	r := NewRegistry(data)
+	assert.NoError(t, r.LoadOverlay(tempFile.Name()))
+
+	model, err := r.Lookup(OpenAI, "external-model")
+	assert.NoError(t, err)
+	assert.Equal(t, "external-model", model.Name())
+
+	model, err = r.Lookup(OpenAI, "gpt-4")
+	assert.NoError(t, err)
+	assert.Equal(t, "gpt-4", model.Name())
+
+	_, err = NewAIModel("external-model")
+	assert.ErrorIs(t, err, ErrModelNotFound)
+}
+
+func TestParseParameters(t *testing.T) {
+	tests := []struct {
+		name             string
+		architectureType ArchitectureType
+		jsonInput        string
+		expected         Parameters
+		expectError      bool
+	}{
+		{
+			name:             "dense model with float parameters",
+			architectureType: DENSE,
+			jsonInput:        `8.03`,
+			expected: Parameters{
+				Total:  common.RangeValue{Min: 8.03, Max: 8.03},
+				Active: common.RangeValue{Min: 8.03, Max: 8.03},
+			},
+		},
+		{
+			name:             "dense model with integer parameters",
+			architectureType: DENSE,
+			jsonInput:        `70`,
+			expected: Parameters{
+				Total:  common.RangeValue{Min: 70, Max: 70},
+				Active: common.RangeValue{Min: 70, Max: 70},
+			},
+		},
+		{
+			name:             "dense model with parameter range",
+			architectureType: DENSE,
+			jsonInput:        `{"min": 20, "max": 70}`,
+			expected: Parameters{
+				Total:  common.RangeValue{Min: 20, Max: 70},
+				Active: common.RangeValue{Min: 20, Max: 70},
+			},
+		},
+		{
+			name:             "dense model with total and active ranges",
+			architectureType: DENSE,
+			jsonInput:        `{"total": {"min": 8, "max": 28}, "active": {"min": 8, "max": 28}}`,
+			expected: Parameters{
+				Total:  common.RangeValue{Min: 8, Max: 28},
+				Active: common.RangeValue{Min: 8, Max: 28},
+			},
+		},
+		{
+			name:             "moe model with scalar active parameters",
+			architectureType: MOE,
+			jsonInput:        `{"total": 46.7, "active": 12.9}`,
+			expected: Parameters{
+				Total:  common.RangeValue{Min: 46.7, Max: 46.7},
+				Active: common.RangeValue{Min: 12.9, Max: 12.9},
+			},
+		},
+		{
+			name:             "moe model with active parameter range",
+			architectureType: MOE,
+			jsonInput:        `{"total": 440, "active": {"min": 55, "max": 220}}`,
+			expected: Parameters{
+				Total:  common.RangeValue{Min: 440, Max: 440},
+				Active: common.RangeValue{Min: 55, Max: 220},
+			},
+		},
+		{
+			name:             "moe model without active parameters is left for validation",
+			architectureType: MOE,
+			jsonInput:        `{"total": 440}`,
+			expected: Parameters{
+				Total: common.RangeValue{Min: 440, Max: 440},
+			},
+		},
+		{
+			name:             "returns error for unsupported parameters",
+			architectureType: DENSE,
+			jsonInput:        `"7b"`,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p fastjson.Parser
+			v, err := p.Parse(tt.jsonInput)
+			assert.NoError(t, err)
+
+			result, err := parseParameters(tt.architectureType, v)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestValidateArchitecture(t *testing.T) {
+	tests := []struct {
+		name         string
+		architecture Architecture
+		expectError  bool
+	}{
+		{
+			name: "accepts dense architecture",
+			architecture: Architecture{
+				Type: DENSE,
+				Parameters: Parameters{
+					Total:  common.RangeValue{Min: 7, Max: 7},
+					Active: common.RangeValue{Min: 7, Max: 7},
+				},
+			},
+		},
+		{
+			name: "rejects moe architecture without active parameters",
+			architecture: Architecture{
+				Type:       MOE,
+				Parameters: Parameters{Total: common.RangeValue{Min: 440, Max: 440}},
+			},
+			expectError: true,
+		},
+		{
+			name: "rejects architecture without total parameters",
+			architecture: Architecture{
+				Type:       DENSE,
+				Parameters: Parameters{Active: common.RangeValue{Min: 7, Max: 7}},
+			},
+			expectError: true,
+		},
+		{
+			name:         "rejects missing architecture type",
+			architecture: Architecture{},
+			expectError:  true,
+		},
+		{
+			name: "rejects unknown architecture type",
+			architecture: Architecture{
+				Type: "sparse",
+				Parameters: Parameters{
+					Total:  common.RangeValue{Min: 7, Max: 7},
+					Active: common.RangeValue{Min: 7, Max: 7},
+				},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateArchitecture(tt.architecture)
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEmbeddedCatalogParameters(t *testing.T) {
+	data, err := fetchEmbeddedAIModels()
+	assert.NoError(t, err)
+
+	for _, model := range data.Models {
+		params := model.Architecture().Parameters
+		assert.Positive(t, params.Active.Max, model.Name())
+		assert.LessOrEqual(t, params.Active.Max, params.Total.Max, model.Name())
+	}
+}
+
+func TestEmbeddedCatalogWarnings(t *testing.T) {
+	model, err := NewAIModel("gpt-4o")
+	assert.NoError(t, err)
+	assert.Equal(t, []Warning{NewWarning(WarningModelArchNotReleased), NewWarning(WarningModelArchMultimodal)},
+		model.Warnings())
+	assert.True(t, model.LowConfidence())
+}
+
+func TestRegisterWarning(t *testing.T) {
+	assert.Error(t, RegisterWarning(WarningDefinition{}))
+	assert.EqualError(t, RegisterWarning(WarningDefinition{Code: "Custom Warning"}),
+		`warning code "Custom Warning" must be in kebab case`)
+
+	assert.NoError(t, RegisterWarning(WarningDefinition{
+		Code:     "test-custom-warning",
+		Message:  "Custom warning.",
+		Severity: SeverityLowConfidence,
+	}))
+	assert.Equal(t,
+		Warning{Code: "test-custom-warning", Message: "Custom warning.", Severity: SeverityLowConfidence},
+		NewWarning("test-custom-warning"))
+}
+
+func TestAIModel_ModelRequiredMemory(t *testing.T) {
+	tests := []struct {
+		name      string
+		precision Precision
+		expected  float64
+	}{
+		{name: "defaults to 8 bit weights", precision: "", expected: 84},
+		{name: "fp32 weights", precision: FP32, expected: 336},
+		{name: "bf16 weights", precision: BF16, expected: 168},
+		{name: "fp16 weights", precision: FP16, expected: 168},
+		{name: "fp8 weights", precision: FP8, expected: 84},
+		{name: "int8 weights", precision: INT8, expected: 84},
+		{name: "int4 weights", precision: INT4, expected: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &AIModel{
+				precision: tt.precision,
+				architecture: Architecture{
+					Type:       DENSE,
+					Parameters: Parameters{Total: common.RangeValue{Min: 70, Max: 70}},
+				},
+			}
+			assert.InDelta(t, tt.expected, model.ModelRequiredMemory(), 1e-9)
+		})
+	}
+}
+
+func TestAIModel_WithPrecision(t *testing.T) {
+	model := &AIModel{name: "llama", precision: BF16}
+
+	quantized, err := model.WithPrecision(INT4)
+	assert.NoError(t, err)
+	assert.Equal(t, INT4, quantized.Precision())
+	assert.Equal(t, BF16, model.Precision())
+
+	_, err = model.WithPrecision("int3")
+	assert.Error(t, err)
+}
+
+func TestFetchAIModels_Precision(t *testing.T) {
+	tests := []struct {
+		name                   string
+		jsonContent            string
+		expectError            bool
+		expectedPrecision      Precision
+		expectedAliasPrecision Precision
+	}{
+		{
+			name: "parses model and alias precision",
+			jsonContent: `{
+				"aliases": [{"provider": "huggingface_hub", "name": "llama-awq", "alias": "llama", "precision": "int4"}],
+				"models": [
+					{
+						"provider": "huggingface_hub",
+						"name": "llama",
+						"precision": "bf16",
+						"architecture": {"type": "dense", "parameters": 8}
+					}
+				]
+			}`,
+			expectedPrecision:      BF16,
+			expectedAliasPrecision: INT4,
+		},
+		{
+			name: "returns error for unknown model precision",
+			jsonContent: `{
+				"models": [
+					{
+						"provider": "huggingface_hub",
+						"name": "llama",
+						"precision": "fp12",
+						"architecture": {"type": "dense", "parameters": 8}
+					}
+				]
+			}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parseAIModels([]byte(tt.jsonContent))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPrecision, data.Models[0].Precision())
+			assert.Equal(t, tt.expectedAliasPrecision, data.Aliases[0].Precision)
+
+			r := NewRegistry(data)
+			deployment, err := r.Lookup(HuggingfaceHub, "llama-awq")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAliasPrecision, deployment.Precision())
+			assert.InDelta(t, 4.8, deployment.ModelRequiredMemory(), 1e-9)
+		})
+	}
+}
+
+func TestEmbeddedCatalogPrecision(t *testing.T) {
+	r, err := DefaultRegistry()
+	assert.NoError(t, err)
+
+	model, err := r.Lookup(HuggingfaceHub, "mistral-community/Mixtral-8x22B-v0.1-AWQ")
+	assert.NoError(t, err)
+	assert.Equal(t, INT4, model.Precision())
+
+	model, err = r.Lookup(HuggingfaceHub, "mistral-community/Mixtral-8x22B-v0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultPrecision, model.Precision())
+}
@@ -0,0 +1,45 @@
+package aimodel
+
+import (
+	"embed"
+	"fmt"
+)
+
+const (
+	// embeddedCatalogPath is the path of the default model catalog inside embeddedCatalog.
+	embeddedCatalogPath = "data/aimodels.json"
+	// embeddedSchemaPath is the path of the catalog JSON Schema inside embeddedCatalog.
+	embeddedSchemaPath = "data/aimodels.schema.json"
+)
+
+//go:embed data/aimodels.json data/aimodels.schema.json
+var embeddedCatalog embed.FS
+
+// CatalogSchema returns the JSON Schema describing the catalog format.
+func CatalogSchema() []byte {
+	schema, err := embeddedCatalog.ReadFile(embeddedSchemaPath)
+	if err != nil {
+		// The schema is embedded at build time, so it can only be missing if the embed directive changes.
+		panic(fmt.Sprintf("embedded catalog schema is missing: %v", err))
+	}
+	return schema
+}
+
+// fetchEmbeddedAIModels parses the model catalog compiled into the binary.
+func fetchEmbeddedAIModels() (*ModelData, error) {
+	data, err := embeddedCatalog.ReadFile(embeddedCatalogPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded catalog: %w", err)
+	}
+	return parseAIModels(data)
+}
+
+// LoadAIModels parses an external JSON or YAML catalog file and adds its models to the default registry.
+// Models in the external catalog replace embedded models with the same provider and name.
+func LoadAIModels(source string) error {
+	r, err := DefaultRegistry()
+	if err != nil {
+		return err
+	}
+	return r.LoadOverlay(source)
+}
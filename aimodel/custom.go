@@ -0,0 +1,121 @@
+package aimodel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/omegabytes/ecologits-go/common"
+	"gopkg.in/yaml.v3"
+)
+
+// ModelSpec describes a custom or private model, eg a fine-tuned or in-house model that is not in the
+// embedded catalog.
+type ModelSpec struct {
+	Name         string
+	Provider     Provider
+	Architecture Architecture
+	Precision    Precision
+	// Task defaults to TaskChat and Modalities to the defaults of the task.
+	Task       Task
+	Modalities Modalities
+	Sources    []string
+	Warnings   []Warning
+}
+
+// NewCustomAIModel creates an AIModel from spec after validating it.
+func NewCustomAIModel(spec ModelSpec) (*AIModel, error) {
+	if spec.Name == "" {
+		return nil, errors.New("name cannot be empty")
+	}
+	if spec.Provider == "" {
+		return nil, errors.New("provider cannot be empty")
+	}
+	if _, err := ParsePrecision(string(spec.Precision)); err != nil {
+		return nil, fmt.Errorf("invalid model %q: %w", spec.Name, err)
+	}
+	task, err := ParseTask(string(spec.Task))
+	if err != nil {
+		return nil, fmt.Errorf("invalid model %q: %w", spec.Name, err)
+	}
+	for _, modality := range append(slices.Clone(spec.Modalities.Input), spec.Modalities.Output...) {
+		if _, err := ParseModality(string(modality)); err != nil {
+			return nil, fmt.Errorf("invalid model %q: %w", spec.Name, err)
+		}
+	}
+
+	architecture := spec.Architecture
+	if architecture.Type == DENSE && architecture.Parameters.Active == (common.RangeValue{}) {
+		architecture.Parameters.Active = architecture.Parameters.Total
+	}
+	if err := validateArchitecture(architecture); err != nil {
+		return nil, fmt.Errorf("invalid architecture for model %q: %w", spec.Name, err)
+	}
+
+	sources := make([]string, len(spec.Sources))
+	copy(sources, spec.Sources)
+	warnings := make([]Warning, len(spec.Warnings))
+	copy(warnings, spec.Warnings)
+
+	return &AIModel{
+		name:         spec.Name,
+		provider:     spec.Provider,
+		architecture: architecture,
+		warnings:     warnings,
+		sources:      sources,
+		precision:    spec.Precision,
+		task:         task,
+		modalities: Modalities{
+			Input:  slices.Clone(spec.Modalities.Input),
+			Output: slices.Clone(spec.Modalities.Output),
+		},
+	}, nil
+}
+
+// Register validates spec and adds the model to the registry, replacing any model with the same provider
+// and name, including models from the embedded catalog.
+func (r *Registry) Register(spec ModelSpec) error {
+	model, err := NewCustomAIModel(spec)
+	if err != nil {
+		return err
+	}
+	r.Add(&ModelData{Models: []AIModel{*model}})
+	return nil
+}
+
+// LoadOverlay reads a JSON or YAML catalog file and adds its models and aliases to the registry.
+// Overlay models replace models with the same provider and name.
+func (r *Registry) LoadOverlay(source string) error {
+	data, err := FetchAIModels(source)
+	if err != nil {
+		return err
+	}
+	r.Add(data)
+	return nil
+}
+
+// isYAML reports whether source is a YAML file, based on its extension.
+func isYAML(source string) bool {
+	switch strings.ToLower(filepath.Ext(source)) {
+	case ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// yamlToJSON converts a YAML catalog to JSON so that it can go through the same parser as JSON catalogs.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	converted, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML to JSON: %w", err)
+	}
+	return converted, nil
+}
@@ -0,0 +1,223 @@
+package aimodel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCustomAIModel(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           ModelSpec
+		expectError    bool
+		expectedActive common.RangeValue
+	}{
+		{
+			name: "creates dense model with active parameters equal to total",
+			spec: ModelSpec{
+				Name:     "acme-chat-13b",
+				Provider: "acme",
+				Architecture: Architecture{
+					Type:       DENSE,
+					Parameters: Parameters{Total: common.RangeValue{Min: 13, Max: 13}},
+				},
+				Precision: INT4,
+			},
+			expectedActive: common.RangeValue{Min: 13, Max: 13},
+		},
+		{
+			name: "creates moe model",
+			spec: ModelSpec{
+				Name:     "acme-moe",
+				Provider: "acme",
+				Architecture: Architecture{
+					Type: MOE,
+					Parameters: Parameters{
+						Total:  common.RangeValue{Min: 100, Max: 100},
+						Active: common.RangeValue{Min: 10, Max: 20},
+					},
+				},
+			},
+			expectedActive: common.RangeValue{Min: 10, Max: 20},
+		},
+		{
+			name: "rejects empty name",
+			spec: ModelSpec{
+				Provider: "acme",
+				Architecture: Architecture{
+					Type:       DENSE,
+					Parameters: Parameters{Total: common.RangeValue{Min: 13, Max: 13}},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "rejects empty provider",
+			spec: ModelSpec{
+				Name: "acme-chat-13b",
+				Architecture: Architecture{
+					Type:       DENSE,
+					Parameters: Parameters{Total: common.RangeValue{Min: 13, Max: 13}},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "rejects min greater than max",
+			spec: ModelSpec{
+				Name:     "acme-chat-13b",
+				Provider: "acme",
+				Architecture: Architecture{
+					Type:       DENSE,
+					Parameters: Parameters{Total: common.RangeValue{Min: 20, Max: 13}},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "rejects active parameters greater than total",
+			spec: ModelSpec{
+				Name:     "acme-moe",
+				Provider: "acme",
+				Architecture: Architecture{
+					Type: MOE,
+					Parameters: Parameters{
+						Total:  common.RangeValue{Min: 100, Max: 100},
+						Active: common.RangeValue{Min: 10, Max: 200},
+					},
+				},
+			},
+			expectError: true,
+		},
+		{
+			name: "rejects unknown precision",
+			spec: ModelSpec{
+				Name:     "acme-chat-13b",
+				Provider: "acme",
+				Architecture: Architecture{
+					Type:       DENSE,
+					Parameters: Parameters{Total: common.RangeValue{Min: 13, Max: 13}},
+				},
+				Precision: "int2",
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := NewCustomAIModel(tt.spec)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.spec.Name, model.Name())
+			assert.Equal(t, tt.spec.Provider, model.Provider())
+			assert.Equal(t, tt.expectedActive, model.Architecture().Parameters.Active)
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := testRegistry()
+
+	err := r.Register(ModelSpec{
+		Name:     "gpt-4",
+		Provider: OpenAI,
+		Architecture: Architecture{
+			Type:       DENSE,
+			Parameters: Parameters{Total: common.RangeValue{Min: 100, Max: 100}},
+		},
+		Sources: []string{"https://example.com"},
+	})
+	assert.NoError(t, err)
+
+	model, err := r.Lookup(OpenAI, "gpt-4")
+	assert.NoError(t, err)
+	assert.Equal(t, DENSE, model.Architecture().Type)
+	assert.Equal(t, []string{"https://example.com"}, model.Sources())
+}
+
+func TestRegistry_LoadOverlay(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		content     string
+		expectError bool
+	}{
+		{
+			name:     "loads JSON overlay",
+			fileName: "overlay.json",
+			content: `{
+				"aliases": [{"provider": "acme", "name": "prod-chat", "alias": "acme-chat"}],
+				"models": [
+					{
+						"provider": "acme",
+						"name": "acme-chat",
+						"architecture": {"type": "dense", "parameters": 13},
+						"warnings": ["model-arch-not-released"]
+					}
+				]
+			}`,
+		},
+		{
+			name:     "loads YAML overlay",
+			fileName: "overlay.yaml",
+			content: `
+aliases:
+  - provider: acme
+    name: prod-chat
+    alias: acme-chat
+models:
+  - provider: acme
+    name: acme-chat
+    architecture:
+      type: dense
+      parameters: 13
+    warnings:
+      - model-arch-not-released
+`,
+		},
+		{
+			name:     "rejects overlay with inconsistent ranges",
+			fileName: "overlay.yml",
+			content: `
+models:
+  - provider: acme
+    name: acme-chat
+    architecture:
+      type: dense
+      parameters:
+        min: 20
+        max: 13
+`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := filepath.Join(t.TempDir(), tt.fileName)
+			assert.NoError(t, os.WriteFile(source, []byte(tt.content), 0o600))
+
+			r := testRegistry()
+			err := r.LoadOverlay(source)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			model, err := r.Lookup("acme", "prod-chat")
+			assert.NoError(t, err)
+			assert.Equal(t, "acme-chat", model.Name())
+			assert.Equal(t, common.RangeValue{Min: 13, Max: 13}, model.Architecture().Parameters.Active)
+			assert.True(t, model.LowConfidence())
+		})
+	}
+}
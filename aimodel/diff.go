@@ -0,0 +1,156 @@
+package aimodel
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/omegabytes/ecologits-go/common"
+)
+
+// CatalogDiff lists the models that differ between two catalogs, identified as "provider/name".
+type CatalogDiff struct {
+	Previous CatalogVersion
+	Next     CatalogVersion
+	Added    []string
+	Removed  []string
+	Changed  []ModelChange
+}
+
+// ModelChange lists the fields that changed for a model present in both catalogs.
+type ModelChange struct {
+	Model  string
+	Fields []FieldChange
+}
+
+// FieldChange is the previous and next value of a changed model field, formatted for display.
+type FieldChange struct {
+	Field    string
+	Previous string
+	Next     string
+}
+
+// Fields compared by DiffCatalogs.
+const (
+	FieldArchitectureType = "architecture.type"
+	FieldTotalParameters  = "parameters.total"
+	FieldActiveParameters = "parameters.active"
+	FieldPrecision        = "precision"
+	FieldTask             = "task"
+	FieldModalities       = "modalities"
+	FieldWarnings         = "warnings"
+	FieldSources          = "sources"
+)
+
+// Empty reports whether the catalogs contain the same models.
+func (d CatalogDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// AffectsEstimates reports whether the change alters impact estimates, ie whether the architecture,
+// parameter ranges, precision or task changed, as opposed to metadata such as sources.
+func (c ModelChange) AffectsEstimates() bool {
+	for _, field := range c.Fields {
+		switch field.Field {
+		case FieldArchitectureType, FieldTotalParameters, FieldActiveParameters, FieldPrecision, FieldTask:
+			return true
+		}
+	}
+	return false
+}
+
+// DiffCatalogs compares the models of two catalogs. A model is changed when its architecture, parameter
+// ranges, precision, task, modalities, warnings or sources differ.
+func DiffCatalogs(previous, next *ModelData) CatalogDiff {
+	previousModels := indexModels(previous)
+	nextModels := indexModels(next)
+
+	var diff CatalogDiff
+	if previous != nil {
+		diff.Previous = previous.CatalogVersion()
+	}
+	if next != nil {
+		diff.Next = next.CatalogVersion()
+	}
+	for key, model := range nextModels {
+		old, ok := previousModels[key]
+		if !ok {
+			diff.Added = append(diff.Added, key.String())
+			continue
+		}
+		if fields := diffModel(old, model); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, ModelChange{Model: key.String(), Fields: fields})
+		}
+	}
+	for key := range previousModels {
+		if _, ok := nextModels[key]; !ok {
+			diff.Removed = append(diff.Removed, key.String())
+		}
+	}
+
+	slices.Sort(diff.Added)
+	slices.Sort(diff.Removed)
+	slices.SortFunc(diff.Changed, func(a, b ModelChange) int { return strings.Compare(a.Model, b.Model) })
+	return diff
+}
+
+func (k modelKey) String() string {
+	return string(k.provider) + "/" + k.name
+}
+
+func indexModels(data *ModelData) map[modelKey]AIModel {
+	models := make(map[modelKey]AIModel)
+	if data == nil {
+		return models
+	}
+	for _, model := range data.Models {
+		models[modelKey{provider: model.provider, name: model.name}] = model
+	}
+	return models
+}
+
+func diffModel(a, b AIModel) []FieldChange {
+	var fields []FieldChange
+	add := func(field, previous, next string) {
+		if previous != next {
+			fields = append(fields, FieldChange{Field: field, Previous: previous, Next: next})
+		}
+	}
+	add(FieldArchitectureType, string(a.architecture.Type), string(b.architecture.Type))
+	add(FieldTotalParameters, formatRange(a.architecture.Parameters.Total),
+		formatRange(b.architecture.Parameters.Total))
+	add(FieldActiveParameters, formatRange(a.architecture.Parameters.Active),
+		formatRange(b.architecture.Parameters.Active))
+	add(FieldPrecision, string(a.Precision()), string(b.Precision()))
+	add(FieldTask, string(a.Task()), string(b.Task()))
+	add(FieldModalities, formatModalities(a.Modalities()), formatModalities(b.Modalities()))
+	add(FieldWarnings, formatWarnings(a.warnings), formatWarnings(b.warnings))
+	add(FieldSources, strings.Join(a.sources, ", "), strings.Join(b.sources, ", "))
+	return fields
+}
+
+func formatRange(value common.RangeValue) string {
+	if value.Min == value.Max {
+		return strconv.FormatFloat(value.Min, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(value.Min, 'g', -1, 64) + "-" + strconv.FormatFloat(value.Max, 'g', -1, 64)
+}
+
+func formatModalities(modalities Modalities) string {
+	format := func(values []Modality) string {
+		names := make([]string, 0, len(values))
+		for _, value := range values {
+			names = append(names, string(value))
+		}
+		return strings.Join(names, "+")
+	}
+	return format(modalities.Input) + " -> " + format(modalities.Output)
+}
+
+func formatWarnings(warnings []Warning) string {
+	codes := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		codes = append(codes, warning.Code)
+	}
+	return strings.Join(codes, ", ")
+}
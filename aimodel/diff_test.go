@@ -0,0 +1,206 @@
+package aimodel
+
+import (
+	"testing"
+
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCatalogs(t *testing.T) {
+	previous := testRegistry()
+	next := testRegistry()
+	assert.NoError(t, next.Register(ModelSpec{
+		Name:     "command-r",
+		Provider: Cohere,
+		Architecture: Architecture{
+			Type:       DENSE,
+			Parameters: Parameters{Total: common.RangeValue{Min: 32, Max: 35}},
+		},
+	}))
+	assert.NoError(t, next.Register(ModelSpec{
+		Name:     "claude-3-haiku",
+		Provider: Anthropic,
+		Architecture: Architecture{
+			Type:       DENSE,
+			Parameters: Parameters{Total: common.RangeValue{Min: 20, Max: 20}},
+		},
+	}))
+
+	previousModels := previous.List()
+	nextModels := next.Filter(func(m AIModel) bool { return m.Name() != "open-mistral-7b" })
+
+	diff := DiffCatalogs(
+		&ModelData{Version: "1", Models: previousModels},
+		&ModelData{Version: "2", Models: nextModels},
+	)
+	assert.Equal(t, "1", diff.Previous.Version)
+	assert.Equal(t, "2", diff.Next.Version)
+	assert.NotEqual(t, diff.Previous.Hash, diff.Next.Hash)
+	assert.Equal(t, []string{"anthropic/claude-3-haiku"}, diff.Added)
+	assert.Equal(t, []string{"mistralai/open-mistral-7b"}, diff.Removed)
+	assert.Equal(t, []ModelChange{
+		{
+			Model: "cohere/command-r",
+			Fields: []FieldChange{
+				{Field: FieldTotalParameters, Previous: "35", Next: "32-35"},
+				{Field: FieldActiveParameters, Previous: "35", Next: "32-35"},
+			},
+		},
+	}, diff.Changed)
+	assert.True(t, diff.Changed[0].AffectsEstimates())
+	assert.False(t, diff.Empty())
+
+	assert.True(t, DiffCatalogs(&ModelData{Models: previousModels}, &ModelData{Models: previousModels}).Empty())
+}
+
+func TestEncodeCatalog(t *testing.T) {
+	upstream := `{
+		"aliases": [{"type": "alias", "provider": "openai", "name": "gpt-35-turbo", "alias": "gpt-3.5-turbo"}],
+		"models": [
+			{
+				"type": "model",
+				"provider": "openai",
+				"name": "gpt-4",
+				"architecture": {"type": "moe", "parameters": {"total": 1760, "active": {"min": 220, "max": 880}}},
+				"warnings": ["model-arch-not-released", {"code": "custom", "message": "Custom warning."}],
+				"sources": ["https://example.com"]
+			},
+			{
+				"type": "model",
+				"provider": "openai",
+				"name": "gpt-3.5-turbo",
+				"precision": "fp16",
+				"architecture": {"type": "dense", "parameters": {"min": 20, "max": 70}},
+				"warnings": null,
+				"sources": []
+			}
+		]
+	}`
+	expected := `{
+    "aliases": [
+        {
+            "provider": "openai",
+            "name": "gpt-35-turbo",
+            "alias": "gpt-3.5-turbo"
+        }
+    ],
+    "models": [
+        {
+            "provider": "openai",
+            "name": "gpt-3.5-turbo",
+            "precision": "fp16",
+            "architecture": {
+                "type": "dense",
+                "parameters": {
+                    "min": 20,
+                    "max": 70
+                }
+            },
+            "warnings": [],
+            "sources": []
+        },
+        {
+            "provider": "openai",
+            "name": "gpt-4",
+            "architecture": {
+                "type": "moe",
+                "parameters": {
+                    "total": 1760,
+                    "active": {
+                        "min": 220,
+                        "max": 880
+                    }
+                }
+            },
+            "warnings": [
+                "model-arch-not-released",
+                {
+                    "code": "custom",
+                    "message": "Custom warning."
+                }
+            ],
+            "sources": [
+                "https://example.com"
+            ]
+        }
+    ]
+}
+`
+
+	data, err := parseAIModels([]byte(upstream))
+	assert.NoError(t, err)
+	encoded, err := EncodeCatalog(data)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(encoded))
+
+	roundTrip, err := parseAIModels(encoded)
+	assert.NoError(t, err)
+	reencoded, err := EncodeCatalog(roundTrip)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(reencoded))
+}
+
+func TestEncodeCatalog_Embedded(t *testing.T) {
+	embedded, err := embeddedCatalog.ReadFile(embeddedCatalogPath)
+	assert.NoError(t, err)
+
+	data, err := parseAIModels(embedded)
+	assert.NoError(t, err)
+	encoded, err := EncodeCatalog(data)
+	assert.NoError(t, err)
+	assert.Equal(t, string(embedded), string(encoded), "embedded catalog is not in canonical form, run catalog sync")
+}
+
+func TestModelChange_AffectsEstimates(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    string
+		expected bool
+	}{
+		{name: "architecture type", field: FieldArchitectureType, expected: true},
+		{name: "total parameters", field: FieldTotalParameters, expected: true},
+		{name: "active parameters", field: FieldActiveParameters, expected: true},
+		{name: "precision", field: FieldPrecision, expected: true},
+		{name: "warnings", field: FieldWarnings, expected: false},
+		{name: "sources", field: FieldSources, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := ModelChange{Model: "openai/gpt-4", Fields: []FieldChange{{Field: tt.field}}}
+			assert.Equal(t, tt.expected, change.AffectsEstimates())
+		})
+	}
+}
+
+func TestCatalogVersion(t *testing.T) {
+	data, err := parseAIModels([]byte(`{
+		"version": "1.2.0",
+		"models": [{"provider": "openai", "name": "gpt-4", "architecture": {"type": "dense", "parameters": 7}}]
+	}`))
+	assert.NoError(t, err)
+	reordered, err := parseAIModels([]byte(`{
+		"models": [{"name": "gpt-4", "architecture": {"parameters": 7, "type": "dense"}, "provider": "openai"}]
+	}`))
+	assert.NoError(t, err)
+
+	version := data.CatalogVersion()
+	assert.Equal(t, "1.2.0", version.Version)
+	assert.Equal(t, version.Hash, reordered.CatalogVersion().Hash)
+	assert.Equal(t, "1.2.0+"+version.Hash, version.String())
+	assert.Equal(t, version, data.Models[0].CatalogVersion())
+}
+
+func TestEmbeddedCatalogVersion(t *testing.T) {
+	data, err := fetchEmbeddedAIModels()
+	assert.NoError(t, err)
+
+	version, err := EmbeddedCatalogVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, data.CatalogVersion(), version)
+
+	model, err := NewAIModel("gpt-4")
+	assert.NoError(t, err)
+	assert.Equal(t, version, model.CatalogVersion())
+}
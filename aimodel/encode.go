@@ -0,0 +1,147 @@
+package aimodel
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/omegabytes/ecologits-go/common"
+)
+
+// catalogFile is the layout of a catalog file written by EncodeCatalog.
+type catalogFile struct {
+	Version string         `json:"version,omitempty"`
+	Aliases []catalogAlias `json:"aliases"`
+	Models  []catalogModel `json:"models"`
+}
+
+type catalogAlias struct {
+	Provider  string    `json:"provider,omitempty"`
+	Name      string    `json:"name"`
+	Alias     string    `json:"alias"`
+	Precision Precision `json:"precision,omitempty"`
+}
+
+type catalogModel struct {
+	Provider     Provider            `json:"provider"`
+	Name         string              `json:"name"`
+	Precision    Precision           `json:"precision,omitempty"`
+	Task         Task                `json:"task,omitempty"`
+	Modalities   *Modalities         `json:"modalities,omitempty"`
+	Architecture catalogArchitecture `json:"architecture"`
+	Warnings     []any               `json:"warnings"`
+	Sources      []string            `json:"sources"`
+}
+
+type catalogArchitecture struct {
+	Type       ArchitectureType `json:"type"`
+	Parameters any              `json:"parameters"`
+}
+
+type catalogParameters struct {
+	Total  any `json:"total"`
+	Active any `json:"active"`
+}
+
+type catalogRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// EncodeCatalog writes data in the canonical catalog format: aliases and models sorted by provider and name,
+// warnings as bare codes unless they carry a custom message, and parameter counts in their shortest form.
+// The output is deterministic so that catalog updates produce reviewable diffs.
+func EncodeCatalog(data *ModelData) ([]byte, error) {
+	file := catalogFile{
+		Version: data.Version,
+		Aliases: make([]catalogAlias, 0, len(data.Aliases)),
+		Models:  make([]catalogModel, 0, len(data.Models)),
+	}
+	for _, alias := range data.Aliases {
+		file.Aliases = append(file.Aliases, catalogAlias{
+			Provider:  alias.ProviderName,
+			Name:      alias.ModelName,
+			Alias:     alias.ModelAlias,
+			Precision: alias.Precision,
+		})
+	}
+	for _, model := range data.Models {
+		file.Models = append(file.Models, encodeModel(model))
+	}
+
+	slices.SortFunc(file.Aliases, func(a, b catalogAlias) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Name, b.Name))
+	})
+	slices.SortFunc(file.Models, func(a, b catalogModel) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Name, b.Name))
+	})
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(file); err != nil {
+		return nil, fmt.Errorf("failed to encode catalog: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeModel(model AIModel) catalogModel {
+	warnings := make([]any, 0, len(model.warnings))
+	for _, warning := range model.warnings {
+		if warning == NewWarning(warning.Code) {
+			warnings = append(warnings, warning.Code)
+		} else {
+			warnings = append(warnings, warning)
+		}
+	}
+	sources := model.sources
+	if sources == nil {
+		sources = []string{}
+	}
+
+	// Chat is the default task and is left out, as are modalities equal to the defaults of the task.
+	var task Task
+	if model.Task() != TaskChat {
+		task = model.Task()
+	}
+	var modalities *Modalities
+	if !model.Modalities().equal(DefaultModalities(model.Task())) {
+		modalities = &model.modalities
+	}
+
+	return catalogModel{
+		Provider:   model.provider,
+		Name:       model.name,
+		Precision:  model.precision,
+		Task:       task,
+		Modalities: modalities,
+		Architecture: catalogArchitecture{
+			Type:       model.architecture.Type,
+			Parameters: encodeParameters(model.architecture),
+		},
+		Warnings: warnings,
+		Sources:  sources,
+	}
+}
+
+// encodeParameters returns a scalar or range for dense models, and total and active counts otherwise.
+func encodeParameters(architecture Architecture) any {
+	params := architecture.Parameters
+	if architecture.Type == DENSE && params.Active == params.Total {
+		return encodeRange(params.Total)
+	}
+	return catalogParameters{
+		Total:  encodeRange(params.Total),
+		Active: encodeRange(params.Active),
+	}
+}
+
+func encodeRange(value common.RangeValue) any {
+	if value.Min == value.Max {
+		return value.Min
+	}
+	return catalogRange{Min: value.Min, Max: value.Max}
+}
@@ -0,0 +1,83 @@
+package aimodel
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxSuggestions is the number of closest catalog names reported by ModelNotFoundError.
+const maxSuggestions = 3
+
+// ErrModelNotFound is returned when a model name cannot be resolved against the catalog.
+// Use errors.As with *ModelNotFoundError to get the requested name and suggestions.
+var ErrModelNotFound = errors.New("model not found")
+
+// ModelNotFoundError describes a failed model lookup and the closest names known to the catalog.
+type ModelNotFoundError struct {
+	Provider    Provider
+	Name        string
+	Suggestions []string
+}
+
+func (e *ModelNotFoundError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %q", ErrModelNotFound, e.Name)
+	if e.Provider != "" {
+		fmt.Fprintf(&b, " for provider %q", e.Provider)
+	}
+	if len(e.Suggestions) > 0 {
+		fmt.Fprintf(&b, ", did you mean %s?", strings.Join(e.Suggestions, ", "))
+	}
+	return b.String()
+}
+
+// Is reports whether target is ErrModelNotFound.
+func (e *ModelNotFoundError) Is(target error) bool {
+	return target == ErrModelNotFound
+}
+
+// closestNames returns up to maxSuggestions candidates ordered by edit distance to name.
+func closestNames(name string, candidates []string) []string {
+	type scored struct {
+		name     string
+		distance int
+	}
+	scores := make([]scored, 0, len(candidates))
+	for _, candidate := range candidates {
+		scores = append(scores, scored{name: candidate, distance: levenshtein(name, candidate)})
+	}
+	slices.SortFunc(scores, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.name, b.name))
+	})
+
+	suggestions := make([]string, 0, maxSuggestions)
+	for _, s := range scores[:min(maxSuggestions, len(scores))] {
+		suggestions = append(suggestions, s.name)
+	}
+	return suggestions
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
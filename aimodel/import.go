@@ -0,0 +1,44 @@
+package aimodel
+
+import "slices"
+
+// ImportUpstreamCatalog converts a catalog in the upstream EcoLogits format into this project's catalog.
+// Upstream does not track precision, so the precision of models and aliases already in current is kept.
+// Upstream only catalogs chat models, so the non-chat models of current, such as embedding models, are kept
+// as well. current may be nil.
+func ImportUpstreamCatalog(upstream, current *ModelData) *ModelData {
+	imported := &ModelData{
+		Version: upstream.Version,
+		Aliases: slices.Clone(upstream.Aliases),
+		Models:  slices.Clone(upstream.Models),
+	}
+	if current == nil {
+		return imported
+	}
+
+	currentModels := indexModels(current)
+	upstreamModels := indexModels(upstream)
+	for i, model := range imported.Models {
+		old, ok := currentModels[modelKey{provider: model.provider, name: model.name}]
+		if ok && model.precision == "" {
+			imported.Models[i].precision = old.precision
+		}
+	}
+	for _, model := range current.Models {
+		_, ok := upstreamModels[modelKey{provider: model.provider, name: model.name}]
+		if !ok && model.Task() != TaskChat {
+			imported.Models = append(imported.Models, model)
+		}
+	}
+	currentAliases := make(map[modelKey]Precision, len(current.Aliases))
+	for _, alias := range current.Aliases {
+		currentAliases[modelKey{provider: Provider(alias.ProviderName), name: alias.ModelName}] = alias.Precision
+	}
+	for i, alias := range imported.Aliases {
+		precision := currentAliases[modelKey{provider: Provider(alias.ProviderName), name: alias.ModelName}]
+		if alias.Precision == "" {
+			imported.Aliases[i].Precision = precision
+		}
+	}
+	return imported
+}
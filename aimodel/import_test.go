@@ -0,0 +1,34 @@
+package aimodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportUpstreamCatalog(t *testing.T) {
+	upstream := &ModelData{
+		Aliases: []Alias{{ProviderName: "huggingface_hub", ModelName: "llama-awq", ModelAlias: "llama"}},
+		Models: []AIModel{
+			{name: "llama", provider: HuggingfaceHub},
+			{name: "mistral", provider: HuggingfaceHub},
+		},
+	}
+	current := &ModelData{
+		Aliases: []Alias{
+			{ProviderName: "huggingface_hub", ModelName: "llama-awq", ModelAlias: "llama", Precision: INT4},
+		},
+		Models: []AIModel{
+			{name: "llama", provider: HuggingfaceHub, precision: BF16},
+			{name: "retired", provider: HuggingfaceHub},
+			{name: "mistral-embed", provider: MistralAI, task: TaskEmbeddings},
+		},
+	}
+
+	imported := ImportUpstreamCatalog(upstream, current)
+	assert.Equal(t, []string{"llama", "mistral", "mistral-embed"}, modelNames(imported.Models))
+	assert.Equal(t, BF16, imported.Models[0].precision)
+	assert.Empty(t, imported.Models[1].precision)
+	assert.Equal(t, INT4, imported.Aliases[0].Precision)
+	assert.Empty(t, upstream.Aliases[0].Precision)
+}
@@ -0,0 +1,150 @@
+package aimodel
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// MatchStrategy describes how a requested model name was matched against the catalog.
+type MatchStrategy string
+
+const (
+	// MatchExact means the requested name is a catalog model name.
+	MatchExact MatchStrategy = "exact"
+	// MatchAlias means the requested name is a registered alias.
+	MatchAlias MatchStrategy = "alias"
+	// MatchNormalized means the requested name matched after normalizing case and separators.
+	MatchNormalized MatchStrategy = "normalized"
+	// MatchVersionless means the requested name matched after removing date or version suffixes.
+	MatchVersionless MatchStrategy = "versionless"
+	// MatchFamily means the requested name matched the entry of its base model family.
+	MatchFamily MatchStrategy = "family"
+)
+
+// Match is the result of matching a requested model name against a Registry.
+type Match struct {
+	Model         *AIModel
+	Strategy      MatchStrategy
+	RequestedName string
+}
+
+// versionSuffixes match the date and version suffixes providers append to snapshot names,
+// eg "gpt-4o-2024-08-06", "claude-3-5-sonnet-20241022", "gpt-4-0613" or "command-r-08-2024".
+var versionSuffixes = []*regexp.Regexp{
+	regexp.MustCompile(`[-@]\d{4}-\d{2}-\d{2}$`),
+	regexp.MustCompile(`[-@]\d{2}-\d{4}$`),
+	regexp.MustCompile(`[-@]\d{8}$`),
+	regexp.MustCompile(`[-@]\d{3,4}$`),
+	regexp.MustCompile(`[-@]v\d+(:\d+)?$`),
+	regexp.MustCompile(`-(latest|preview|exp)$`),
+}
+
+// Match resolves name for provider against the registry. It tries, in order, the exact name and aliases,
+// a case and separator insensitive match, the name without date or version suffixes, and finally the
+// closest base model family. Models matched by a fuzzy strategy carry a warning naming the requested
+// name and the strategy used. An empty provider matches models from any provider.
+func (r *Registry) Match(provider Provider, name string) (Match, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key := modelKey{provider: provider, name: name}
+	if model, ok := r.findModel(key); ok {
+		return Match{Model: &model, Strategy: MatchExact, RequestedName: name}, nil
+	}
+	model, err := r.resolve(key)
+	if err == nil {
+		return Match{Model: &model, Strategy: MatchAlias, RequestedName: name}, nil
+	}
+	if !errors.Is(err, ErrModelNotFound) {
+		return Match{}, err
+	}
+
+	candidates := r.names(provider)
+	strategy, candidate, ok := matchCandidate(name, candidates)
+	if !ok {
+		return Match{}, err
+	}
+	model, resolveErr := r.resolve(modelKey{provider: provider, name: candidate})
+	if resolveErr != nil {
+		return Match{}, resolveErr
+	}
+	warning := NewWarning(WarningModelNameFuzzyMatch)
+	warning.Message = fmt.Sprintf(
+		"requested model %q matched catalog entry %q using %s matching", name, model.name, strategy)
+	model.warnings = append(slices.Clip(model.warnings), warning)
+	return Match{Model: &model, Strategy: strategy, RequestedName: name}, nil
+}
+
+// matchCandidate returns the candidate name matching name with the least fuzzy strategy.
+func matchCandidate(name string, candidates []string) (MatchStrategy, string, bool) {
+	normalized := make(map[string]string, len(candidates))
+	versionless := make(map[string][]string, len(candidates))
+	for _, candidate := range candidates {
+		n := normalizeModelName(candidate)
+		normalized[n] = candidate
+		base := stripVersion(n)
+		versionless[base] = append(versionless[base], candidate)
+	}
+
+	requested := normalizeModelName(name)
+	if candidate, ok := normalized[requested]; ok {
+		return MatchNormalized, candidate, true
+	}
+
+	base := stripVersion(requested)
+	if matches, ok := versionless[base]; ok {
+		return MatchVersionless, preferredVersion(base, matches), true
+	}
+
+	for family := base; strings.Contains(family, "-"); {
+		family = family[:strings.LastIndex(family, "-")]
+		if matches, ok := versionless[family]; ok {
+			return MatchFamily, preferredVersion(family, matches), true
+		}
+	}
+	return "", "", false
+}
+
+// normalizeModelName lowercases name, drops the "models/" prefix used by some APIs, unifies
+// separators to "-" and removes dots, so that "GPT_3.5_Turbo" and "gpt-35-turbo" compare equal.
+func normalizeModelName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimPrefix(name, "models/")
+	name = strings.NewReplacer("_", "-", " ", "-", ".", "").Replace(name)
+	return name
+}
+
+// stripVersion removes trailing date and version suffixes from a normalized name.
+func stripVersion(name string) string {
+	for {
+		stripped := name
+		for _, suffix := range versionSuffixes {
+			stripped = suffix.ReplaceAllString(stripped, "")
+		}
+		if stripped == name || stripped == "" {
+			return name
+		}
+		name = stripped
+	}
+}
+
+// preferredVersion picks among candidates sharing a base name: the unversioned entry, then the
+// "-latest" entry, then the most recent snapshot by name.
+func preferredVersion(base string, candidates []string) string {
+	sorted := slices.Clone(candidates)
+	slices.Sort(sorted)
+	for _, candidate := range sorted {
+		if normalizeModelName(candidate) == base {
+			return candidate
+		}
+	}
+	for _, candidate := range sorted {
+		if normalizeModelName(candidate) == base+"-latest" {
+			return candidate
+		}
+	}
+	return sorted[len(sorted)-1]
+}
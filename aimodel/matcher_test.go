@@ -0,0 +1,122 @@
+package aimodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Match(t *testing.T) {
+	r, err := DefaultRegistry()
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		provider         Provider
+		requestedName    string
+		expectError      bool
+		expectedName     string
+		expectedStrategy MatchStrategy
+	}{
+		{
+			name:             "matches exact name",
+			provider:         OpenAI,
+			requestedName:    "gpt-4o-2024-08-06",
+			expectedName:     "gpt-4o-2024-08-06",
+			expectedStrategy: MatchExact,
+		},
+		{
+			name:             "matches alias",
+			provider:         OpenAI,
+			requestedName:    "gpt-35-turbo",
+			expectedName:     "gpt-3.5-turbo",
+			expectedStrategy: MatchAlias,
+		},
+		{
+			name:             "matches regardless of case and separators",
+			provider:         OpenAI,
+			requestedName:    "GPT_4o_Mini",
+			expectedName:     "gpt-4o-mini",
+			expectedStrategy: MatchNormalized,
+		},
+		{
+			name:             "matches unknown dated snapshot to the unversioned entry",
+			provider:         OpenAI,
+			requestedName:    "gpt-4o-2025-01-31",
+			expectedName:     "gpt-4o",
+			expectedStrategy: MatchVersionless,
+		},
+		{
+			name:             "matches unknown snapshot to the latest entry",
+			provider:         Anthropic,
+			requestedName:    "claude-3-5-sonnet-20250101",
+			expectedName:     "claude-3-5-sonnet-latest",
+			expectedStrategy: MatchVersionless,
+		},
+		{
+			name:             "matches vertex style snapshot",
+			provider:         Anthropic,
+			requestedName:    "claude-3-opus@20240229",
+			expectedName:     "claude-3-opus-latest",
+			expectedStrategy: MatchVersionless,
+		},
+		{
+			name:             "matches base family",
+			provider:         OpenAI,
+			requestedName:    "gpt-4o-mini-search-preview-2025-03-11",
+			expectedName:     "gpt-4o-mini",
+			expectedStrategy: MatchFamily,
+		},
+		{
+			name:          "returns error when nothing matches",
+			provider:      OpenAI,
+			requestedName: "davinci",
+			expectError:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := r.Match(tt.provider, tt.requestedName)
+			if tt.expectError {
+				assert.ErrorIs(t, err, ErrModelNotFound)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedName, match.Model.Name())
+			assert.Equal(t, tt.expectedStrategy, match.Strategy)
+			assert.Equal(t, tt.requestedName, match.RequestedName)
+
+			hasWarning := false
+			for _, warning := range match.Model.Warnings() {
+				if warning.Code == WarningModelNameFuzzyMatch {
+					hasWarning = true
+				}
+			}
+			fuzzy := tt.expectedStrategy != MatchExact && tt.expectedStrategy != MatchAlias
+			assert.Equal(t, fuzzy, hasWarning)
+		})
+	}
+}
+
+func TestStripVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "gpt-4o-2024-08-06", expected: "gpt-4o"},
+		{input: "claude-3-5-sonnet-20241022", expected: "claude-3-5-sonnet"},
+		{input: "gpt-4-0613", expected: "gpt-4"},
+		{input: "command-r-08-2024", expected: "command-r"},
+		{input: "gemini-15-pro-002", expected: "gemini-15-pro"},
+		{input: "anthropicclaude-3-sonnet-20240229-v1:0", expected: "anthropicclaude-3-sonnet"},
+		{input: "mistral-large-latest", expected: "mistral-large"},
+		{input: "gpt-4", expected: "gpt-4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, stripVersion(tt.input))
+		})
+	}
+}
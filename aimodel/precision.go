@@ -0,0 +1,46 @@
+package aimodel
+
+import "fmt"
+
+// Precision is the numeric format the model weights are served in, eg "bf16" or "int4".
+type Precision string
+
+const (
+	FP32 Precision = "fp32"
+	BF16 Precision = "bf16"
+	FP16 Precision = "fp16"
+	FP8  Precision = "fp8"
+	INT8 Precision = "int8"
+	INT4 Precision = "int4"
+)
+
+// DefaultPrecision is assumed when neither the catalog nor the caller specify a precision.
+const DefaultPrecision = INT8
+
+// ParsePrecision returns the Precision named by s. An empty string returns an empty Precision.
+func ParsePrecision(s string) (Precision, error) {
+	precision := Precision(s)
+	if precision == "" {
+		return "", nil
+	}
+	if _, err := precision.Bits(); err != nil {
+		return "", err
+	}
+	return precision, nil
+}
+
+// Bits returns the number of bits used to store a single weight.
+func (p Precision) Bits() (float64, error) {
+	switch p {
+	case FP32:
+		return 32, nil
+	case BF16, FP16:
+		return 16, nil
+	case FP8, INT8:
+		return 8, nil
+	case INT4:
+		return 4, nil
+	default:
+		return 0, fmt.Errorf("unknown precision %q", p)
+	}
+}
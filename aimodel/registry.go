@@ -0,0 +1,314 @@
+package aimodel
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/omegabytes/ecologits-go/common"
+)
+
+// maxAliasDepth bounds the number of aliases followed when resolving a chained alias.
+const maxAliasDepth = 16
+
+var (
+	defaultRegistryOnce sync.Once
+	defaultRegistryErr  error
+	defaultRegistry     *Registry
+	// defaultCatalogVersion is the version of the embedded catalog DefaultRegistry was populated from.
+	defaultCatalogVersion CatalogVersion
+)
+
+// modelKey identifies a model in a Registry.
+type modelKey struct {
+	provider Provider
+	name     string
+}
+
+// aliasEntry is the name an alias resolves to and the precision of the deployment it describes, if any.
+type aliasEntry struct {
+	target    modelKey
+	precision Precision
+}
+
+// Registry is a concurrency-safe, in-memory index of AI models keyed by provider and name.
+type Registry struct {
+	mu     sync.RWMutex
+	models map[modelKey]AIModel
+	// byName indexes models by name alone, for lookups that do not specify a provider.
+	byName map[string]modelKey
+	// aliases maps an alternative name to the name it resolves to. Aliases with an empty
+	// provider apply to every provider.
+	aliases map[modelKey]aliasEntry
+	// aliasesByName indexes aliases by name alone, for lookups that do not specify a provider.
+	aliasesByName map[string]aliasEntry
+}
+
+// NewRegistry returns a registry populated with the models in data.
+func NewRegistry(data *ModelData) *Registry {
+	r := &Registry{
+		models:        make(map[modelKey]AIModel),
+		byName:        make(map[string]modelKey),
+		aliases:       make(map[modelKey]aliasEntry),
+		aliasesByName: make(map[string]aliasEntry),
+	}
+	if data != nil {
+		r.Add(data)
+	}
+	return r
+}
+
+// DefaultRegistry returns the process-wide registry, parsing the embedded catalog on first use.
+func DefaultRegistry() (*Registry, error) {
+	defaultRegistryOnce.Do(func() {
+		data, err := fetchEmbeddedAIModels()
+		if err != nil {
+			defaultRegistryErr = err
+			return
+		}
+		defaultRegistry = NewRegistry(data)
+		defaultCatalogVersion = data.CatalogVersion()
+	})
+	return defaultRegistry, defaultRegistryErr
+}
+
+// Add inserts the models and aliases in data, replacing existing entries with the same provider and name.
+func (r *Registry) Add(data *ModelData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, model := range data.Models {
+		key := modelKey{provider: model.provider, name: model.name}
+		r.models[key] = model
+		r.byName[model.name] = key
+	}
+	for _, alias := range data.Aliases {
+		r.addAlias(alias)
+	}
+}
+
+// RegisterAlias adds an alternative name for a model, such as the name of a fine-tuned model or of an
+// Azure deployment. The alias may point at another alias. An alias with an empty provider applies to
+// every provider.
+func (r *Registry) RegisterAlias(alias Alias) error {
+	if alias.ModelName == "" || alias.ModelAlias == "" {
+		return errors.New("alias name and target cannot be empty")
+	}
+	if alias.ModelName == alias.ModelAlias {
+		return fmt.Errorf("alias %q cannot point at itself", alias.ModelName)
+	}
+	if _, err := ParsePrecision(string(alias.Precision)); err != nil {
+		return fmt.Errorf("invalid alias %q: %w", alias.ModelName, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := modelKey{provider: Provider(alias.ProviderName), name: alias.ModelName}
+	if _, ok := r.findModel(key); ok {
+		return fmt.Errorf("alias %q shadows an existing model", alias.ModelName)
+	}
+	if r.reaches(modelKey{provider: key.provider, name: alias.ModelAlias}, key) {
+		return fmt.Errorf("%w: %q", errAliasCycle, alias.ModelName)
+	}
+	r.addAlias(alias)
+	return nil
+}
+
+// clone returns a copy of the registry that can be modified independently.
+func (r *Registry) clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return &Registry{
+		models:        maps.Clone(r.models),
+		byName:        maps.Clone(r.byName),
+		aliases:       maps.Clone(r.aliases),
+		aliasesByName: maps.Clone(r.aliasesByName),
+	}
+}
+
+// Lookup returns the model registered under provider and name, following aliases if needed.
+// An empty provider matches a model of that name from any provider.
+func (r *Registry) Lookup(provider Provider, name string) (*AIModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	model, err := r.resolve(modelKey{provider: provider, name: name})
+	if err != nil {
+		return nil, err
+	}
+	return &model, nil
+}
+
+// Len returns the number of registered models.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.models)
+}
+
+// List returns every registered model, sorted by provider and name.
+func (r *Registry) List() []AIModel {
+	return r.Filter(func(AIModel) bool { return true })
+}
+
+// ByProvider returns the models served by provider, sorted by name.
+func (r *Registry) ByProvider(provider Provider) []AIModel {
+	return r.Filter(func(m AIModel) bool { return m.provider == provider })
+}
+
+// ByArchitecture returns the models with the given architecture type, sorted by provider and name.
+func (r *Registry) ByArchitecture(architectureType ArchitectureType) []AIModel {
+	return r.Filter(func(m AIModel) bool { return m.architecture.Type == architectureType })
+}
+
+// ByTask returns the models serving the given task, sorted by provider and name.
+func (r *Registry) ByTask(task Task) []AIModel {
+	return r.Filter(func(m AIModel) bool { return m.Task() == task })
+}
+
+// ByTotalParameters returns the models whose total parameter range (in billions) lies within bounds.
+func (r *Registry) ByTotalParameters(bounds common.RangeValue) []AIModel {
+	return r.Filter(func(m AIModel) bool { return withinRange(m.architecture.Parameters.Total, bounds) })
+}
+
+// ByActiveParameters returns the models whose active parameter range (in billions) lies within bounds.
+func (r *Registry) ByActiveParameters(bounds common.RangeValue) []AIModel {
+	return r.Filter(func(m AIModel) bool { return withinRange(m.architecture.Parameters.Active, bounds) })
+}
+
+// Filter returns the models for which keep returns true, sorted by provider and name.
+func (r *Registry) Filter(keep func(AIModel) bool) []AIModel {
+	r.mu.RLock()
+	models := make([]AIModel, 0, len(r.models))
+	for _, model := range r.models {
+		if keep(model) {
+			models = append(models, model)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(models, func(a, b AIModel) int {
+		return cmp.Or(cmp.Compare(a.provider, b.provider), cmp.Compare(a.name, b.name))
+	})
+	return models
+}
+
+// errAliasCycle is returned when following aliases leads back to an alias already visited.
+var errAliasCycle = errors.New("alias cycle detected")
+
+func (r *Registry) addAlias(alias Alias) {
+	key := modelKey{provider: Provider(alias.ProviderName), name: alias.ModelName}
+	entry := aliasEntry{
+		target:    modelKey{provider: Provider(alias.ProviderName), name: alias.ModelAlias},
+		precision: alias.Precision,
+	}
+	r.aliases[key] = entry
+	r.aliasesByName[alias.ModelName] = entry
+}
+
+// resolve returns the model for key, following chained aliases. The precision of the first alias in the
+// chain that declares one overrides the precision of the model. The caller must hold r.mu.
+func (r *Registry) resolve(key modelKey) (AIModel, error) {
+	requested := key
+	seen := make(map[modelKey]bool)
+	var precision Precision
+	for range maxAliasDepth {
+		if model, ok := r.findModel(key); ok {
+			if precision != "" {
+				model.precision = precision
+			}
+			return model, nil
+		}
+		entry, ok := r.findAlias(key)
+		if !ok {
+			break
+		}
+		if precision == "" {
+			precision = entry.precision
+		}
+		target := entry.target
+		if seen[target] {
+			return AIModel{}, fmt.Errorf("%w: resolving %q", errAliasCycle, requested.name)
+		}
+		seen[target] = true
+		if target.provider == "" {
+			target.provider = key.provider
+		}
+		key = target
+	}
+	return AIModel{}, &ModelNotFoundError{
+		Provider:    requested.provider,
+		Name:        requested.name,
+		Suggestions: closestNames(requested.name, r.names(requested.provider)),
+	}
+}
+
+// names returns the model and alias names known for provider. An empty provider returns every name.
+// The caller must hold r.mu.
+func (r *Registry) names(provider Provider) []string {
+	names := make([]string, 0, len(r.models)+len(r.aliases))
+	for key := range r.models {
+		if provider == "" || key.provider == provider {
+			names = append(names, key.name)
+		}
+	}
+	for key := range r.aliases {
+		if provider == "" || key.provider == provider || key.provider == "" {
+			names = append(names, key.name)
+		}
+	}
+	return names
+}
+
+// reaches reports whether following aliases from key arrives at target. The caller must hold r.mu.
+func (r *Registry) reaches(key, target modelKey) bool {
+	for range maxAliasDepth {
+		if key == target {
+			return true
+		}
+		entry, ok := r.findAlias(key)
+		if !ok {
+			return false
+		}
+		next := entry.target
+		if next.provider == "" {
+			next.provider = key.provider
+		}
+		key = next
+	}
+	return true
+}
+
+// findModel returns the model registered under key. An empty provider matches any provider.
+func (r *Registry) findModel(key modelKey) (AIModel, bool) {
+	if key.provider == "" {
+		byName, ok := r.byName[key.name]
+		if !ok {
+			return AIModel{}, false
+		}
+		key = byName
+	}
+	model, ok := r.models[key]
+	return model, ok
+}
+
+// findAlias returns the alias registered under key. Provider-scoped aliases take precedence over
+// aliases that apply to every provider.
+func (r *Registry) findAlias(key modelKey) (aliasEntry, bool) {
+	if key.provider == "" {
+		entry, ok := r.aliasesByName[key.name]
+		return entry, ok
+	}
+	if entry, ok := r.aliases[key]; ok {
+		return entry, true
+	}
+	entry, ok := r.aliases[modelKey{name: key.name}]
+	return entry, ok
+}
+
+func withinRange(value, bounds common.RangeValue) bool {
+	return value.Min >= bounds.Min && value.Max <= bounds.Max
+}
@@ -0,0 +1,317 @@
+package aimodel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRegistry() *Registry {
+	return NewRegistry(&ModelData{
+		Models: []AIModel{
+			{
+				name:     "gpt-4",
+				provider: OpenAI,
+				architecture: Architecture{
+					Type: MOE,
+					Parameters: Parameters{
+						Total:  common.RangeValue{Min: 1760, Max: 1760},
+						Active: common.RangeValue{Min: 220, Max: 880},
+					},
+				},
+			},
+			{
+				name:     "open-mistral-7b",
+				provider: MistralAI,
+				architecture: Architecture{
+					Type: DENSE,
+					Parameters: Parameters{
+						Total:  common.RangeValue{Min: 7.3, Max: 7.3},
+						Active: common.RangeValue{Min: 7.3, Max: 7.3},
+					},
+				},
+			},
+			{
+				name:     "command-r",
+				provider: Cohere,
+				architecture: Architecture{
+					Type: DENSE,
+					Parameters: Parameters{
+						Total:  common.RangeValue{Min: 35, Max: 35},
+						Active: common.RangeValue{Min: 35, Max: 35},
+					},
+				},
+			},
+		},
+	})
+}
+
+func modelNames(models []AIModel) []string {
+	names := make([]string, 0, len(models))
+	for _, model := range models {
+		names = append(names, model.Name())
+	}
+	return names
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     Provider
+		modelName    string
+		expectError  bool
+		expectedName string
+	}{
+		{
+			name:         "returns model for provider and name",
+			provider:     OpenAI,
+			modelName:    "gpt-4",
+			expectedName: "gpt-4",
+		},
+		{
+			name:         "returns model for name when provider is empty",
+			modelName:    "command-r",
+			expectedName: "command-r",
+		},
+		{
+			name:        "returns error when provider does not match",
+			provider:    Anthropic,
+			modelName:   "gpt-4",
+			expectError: true,
+		},
+		{
+			name:        "returns error for unknown name",
+			provider:    OpenAI,
+			modelName:   "gpt-unknown",
+			expectError: true,
+		},
+	}
+
+	r := testRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := r.Lookup(tt.provider, tt.modelName)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedName, model.Name())
+		})
+	}
+}
+
+func TestRegistry_Filters(t *testing.T) {
+	r := testRegistry()
+
+	tests := []struct {
+		name     string
+		got      []AIModel
+		expected []string
+	}{
+		{
+			name:     "List returns every model sorted by provider and name",
+			got:      r.List(),
+			expected: []string{"command-r", "open-mistral-7b", "gpt-4"},
+		},
+		{
+			name:     "ByProvider returns models of the provider",
+			got:      r.ByProvider(MistralAI),
+			expected: []string{"open-mistral-7b"},
+		},
+		{
+			name:     "ByArchitecture returns models of the architecture type",
+			got:      r.ByArchitecture(DENSE),
+			expected: []string{"command-r", "open-mistral-7b"},
+		},
+		{
+			name:     "ByTotalParameters returns models within bounds",
+			got:      r.ByTotalParameters(common.RangeValue{Min: 0, Max: 50}),
+			expected: []string{"command-r", "open-mistral-7b"},
+		},
+		{
+			name:     "ByActiveParameters excludes models partially outside bounds",
+			got:      r.ByActiveParameters(common.RangeValue{Min: 30, Max: 500}),
+			expected: []string{"command-r"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, modelNames(tt.got))
+		})
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	r, err := DefaultRegistry()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, r.ByProvider(Anthropic))
+
+	again, err := DefaultRegistry()
+	assert.NoError(t, err)
+	assert.Same(t, r, again)
+}
+
+func TestRegistry_Aliases(t *testing.T) {
+	r := testRegistry()
+	r.Add(&ModelData{
+		Aliases: []Alias{
+			{ProviderName: "openai", ModelName: "gpt-4-deployment", ModelAlias: "gpt-4"},
+		},
+	})
+	assert.NoError(t, r.RegisterAlias(Alias{ProviderName: "openai", ModelName: "prod-gpt", ModelAlias: "gpt-4-deployment"}))
+	assert.NoError(t, r.RegisterAlias(Alias{ModelName: "mistral-7b", ModelAlias: "open-mistral-7b"}))
+
+	tests := []struct {
+		name         string
+		provider     Provider
+		modelName    string
+		expectError  bool
+		expectedName string
+	}{
+		{
+			name:         "resolves catalog alias",
+			provider:     OpenAI,
+			modelName:    "gpt-4-deployment",
+			expectedName: "gpt-4",
+		},
+		{
+			name:         "resolves chained alias",
+			provider:     OpenAI,
+			modelName:    "prod-gpt",
+			expectedName: "gpt-4",
+		},
+		{
+			name:         "resolves alias without provider",
+			modelName:    "prod-gpt",
+			expectedName: "gpt-4",
+		},
+		{
+			name:        "does not resolve provider-scoped alias for another provider",
+			provider:    Anthropic,
+			modelName:   "prod-gpt",
+			expectError: true,
+		},
+		{
+			name:         "resolves alias that applies to every provider",
+			provider:     MistralAI,
+			modelName:    "mistral-7b",
+			expectedName: "open-mistral-7b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := r.Lookup(tt.provider, tt.modelName)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedName, model.Name())
+		})
+	}
+}
+
+func TestRegistry_RegisterAlias(t *testing.T) {
+	tests := []struct {
+		name        string
+		aliases     []Alias
+		expectError bool
+	}{
+		{
+			name:    "registers alias",
+			aliases: []Alias{{ProviderName: "openai", ModelName: "my-gpt", ModelAlias: "gpt-4"}},
+		},
+		{
+			name:        "rejects empty alias",
+			aliases:     []Alias{{ProviderName: "openai", ModelAlias: "gpt-4"}},
+			expectError: true,
+		},
+		{
+			name:        "rejects alias pointing at itself",
+			aliases:     []Alias{{ProviderName: "openai", ModelName: "gpt-x", ModelAlias: "gpt-x"}},
+			expectError: true,
+		},
+		{
+			name:        "rejects alias shadowing a model",
+			aliases:     []Alias{{ProviderName: "openai", ModelName: "gpt-4", ModelAlias: "gpt-4o"}},
+			expectError: true,
+		},
+		{
+			name: "rejects alias cycle",
+			aliases: []Alias{
+				{ProviderName: "openai", ModelName: "a", ModelAlias: "b"},
+				{ProviderName: "openai", ModelName: "b", ModelAlias: "a"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testRegistry()
+			var err error
+			for _, alias := range tt.aliases {
+				err = r.RegisterAlias(alias)
+			}
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDefaultRegistry_CatalogAliases(t *testing.T) {
+	r, err := DefaultRegistry()
+	assert.NoError(t, err)
+
+	for _, name := range []string{"gpt-3.5-turbo", "gpt-35-turbo"} {
+		model, err := r.Lookup(OpenAI, name)
+		assert.NoError(t, err)
+		assert.Equal(t, "gpt-3.5-turbo", model.Name())
+	}
+}
+
+func TestRegistry_LookupNotFound(t *testing.T) {
+	r := testRegistry()
+
+	_, err := r.Lookup(OpenAI, "gpt-4x")
+	assert.ErrorIs(t, err, ErrModelNotFound)
+
+	var notFound *ModelNotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, OpenAI, notFound.Provider)
+	assert.Equal(t, "gpt-4x", notFound.Name)
+	assert.Equal(t, []string{"gpt-4"}, notFound.Suggestions)
+	assert.EqualError(t, err, `model not found: "gpt-4x" for provider "openai", did you mean gpt-4?`)
+
+	_, err = r.Lookup("", "command")
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, []string{"command-r", "gpt-4", "open-mistral-7b"}, notFound.Suggestions)
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "gpt-4", b: "", expected: 5},
+		{a: "gpt-4", b: "gpt-4", expected: 0},
+		{a: "gpt-4", b: "gpt-4o", expected: 1},
+		{a: "claude", b: "clause", expected: 1},
+		{a: "kitten", b: "sitting", expected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.expected, levenshtein(tt.a, tt.b))
+		})
+	}
+}
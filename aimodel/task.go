@@ -0,0 +1,90 @@
+package aimodel
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Task is the kind of request a model serves, eg "chat" or "embeddings".
+type Task string
+
+const (
+	TaskChat            Task = "chat"
+	TaskEmbeddings      Task = "embeddings"
+	TaskImageGeneration Task = "image-generation"
+	TaskSpeechToText    Task = "speech-to-text"
+	TaskTextToSpeech    Task = "text-to-speech"
+	TaskReranking       Task = "reranking"
+)
+
+// Modality is a kind of data a model consumes or produces.
+type Modality string
+
+const (
+	ModalityText  Modality = "text"
+	ModalityImage Modality = "image"
+	ModalityAudio Modality = "audio"
+	// ModalityEmbedding is the vector output of embedding models.
+	ModalityEmbedding Modality = "embedding"
+	// ModalityScore is the relevance score output of reranking models.
+	ModalityScore Modality = "score"
+)
+
+// Modalities are the kinds of data a model consumes and produces.
+type Modalities struct {
+	Input  []Modality `json:"input"`
+	Output []Modality `json:"output"`
+}
+
+// Tasks returns every known Task.
+func Tasks() []Task {
+	return []Task{TaskChat, TaskEmbeddings, TaskImageGeneration, TaskSpeechToText, TaskTextToSpeech, TaskReranking}
+}
+
+// ParseTask returns the Task named by s. An empty string returns TaskChat, the task of catalog entries
+// that do not declare one.
+func ParseTask(s string) (Task, error) {
+	if s == "" {
+		return TaskChat, nil
+	}
+	task := Task(s)
+	if !slices.Contains(Tasks(), task) {
+		return "", fmt.Errorf("unknown task %q", s)
+	}
+	return task, nil
+}
+
+// ParseModality returns the Modality named by s.
+func ParseModality(s string) (Modality, error) {
+	modality := Modality(s)
+	switch modality {
+	case ModalityText, ModalityImage, ModalityAudio, ModalityEmbedding, ModalityScore:
+		return modality, nil
+	default:
+		return "", fmt.Errorf("unknown modality %q", s)
+	}
+}
+
+// DefaultModalities returns the modalities of a typical model for task.
+func DefaultModalities(task Task) Modalities {
+	switch task {
+	case TaskEmbeddings:
+		return Modalities{Input: []Modality{ModalityText}, Output: []Modality{ModalityEmbedding}}
+	case TaskImageGeneration:
+		return Modalities{Input: []Modality{ModalityText}, Output: []Modality{ModalityImage}}
+	case TaskSpeechToText:
+		return Modalities{Input: []Modality{ModalityAudio}, Output: []Modality{ModalityText}}
+	case TaskTextToSpeech:
+		return Modalities{Input: []Modality{ModalityText}, Output: []Modality{ModalityAudio}}
+	case TaskReranking:
+		return Modalities{Input: []Modality{ModalityText}, Output: []Modality{ModalityScore}}
+	case TaskChat:
+		return Modalities{Input: []Modality{ModalityText}, Output: []Modality{ModalityText}}
+	default:
+		return Modalities{}
+	}
+}
+
+func (m Modalities) equal(other Modalities) bool {
+	return slices.Equal(m.Input, other.Input) && slices.Equal(m.Output, other.Output)
+}
@@ -0,0 +1,87 @@
+package aimodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTask(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    Task
+		expectError string
+	}{
+		{name: "defaults to chat", input: "", expected: TaskChat},
+		{name: "parses embeddings", input: "embeddings", expected: TaskEmbeddings},
+		{name: "parses text to speech", input: "text-to-speech", expected: TaskTextToSpeech},
+		{name: "rejects unknown tasks", input: "video-generation", expectError: `unknown task "video-generation"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTask(tt.input)
+			if tt.expectError != "" {
+				assert.EqualError(t, err, tt.expectError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestFetchAIModels_Task(t *testing.T) {
+	data, err := parseAIModels([]byte(`{
+		"models": [
+			{
+				"provider": "openai",
+				"name": "gpt-4o",
+				"modalities": {"input": ["text", "image", "audio"], "output": ["text", "audio"]},
+				"architecture": {"type": "dense", "parameters": 200}
+			},
+			{
+				"provider": "openai",
+				"name": "text-embedding-3-small",
+				"task": "embeddings",
+				"architecture": {"type": "dense", "parameters": 1}
+			},
+			{
+				"provider": "openai",
+				"name": "gpt-4",
+				"architecture": {"type": "dense", "parameters": 1760}
+			}
+		]
+	}`))
+	require.NoError(t, err)
+	require.Len(t, data.Models, 3)
+
+	multimodal := data.Models[0]
+	assert.Equal(t, TaskChat, multimodal.Task())
+	assert.Equal(t, Modalities{
+		Input:  []Modality{ModalityText, ModalityImage, ModalityAudio},
+		Output: []Modality{ModalityText, ModalityAudio},
+	}, multimodal.Modalities())
+
+	embedding := data.Models[1]
+	assert.Equal(t, TaskEmbeddings, embedding.Task())
+	assert.Equal(t, DefaultModalities(TaskEmbeddings), embedding.Modalities())
+
+	chat := data.Models[2]
+	assert.Equal(t, TaskChat, chat.Task())
+	assert.Equal(t, Modalities{Input: []Modality{ModalityText}, Output: []Modality{ModalityText}}, chat.Modalities())
+
+	encoded, err := EncodeCatalog(data)
+	require.NoError(t, err)
+	decoded, err := parseAIModels(encoded)
+	require.NoError(t, err)
+	assert.True(t, DiffCatalogs(data, decoded).Empty())
+	assert.NotContains(t, string(encoded), `"task": "chat"`)
+
+	r := NewRegistry(data)
+	assert.Equal(t, []string{"text-embedding-3-small"}, modelNames(r.ByTask(TaskEmbeddings)))
+
+	_, err = parseAIModels([]byte(`{"models": [{"provider": "openai", "name": "x", "task": "video"}]}`))
+	assert.EqualError(t, err, `failed to parse task of model "x": unknown task "video"`)
+}
@@ -0,0 +1,265 @@
+package aimodel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/valyala/fastjson"
+)
+
+// KnownProviders returns the providers declared as Provider constants.
+func KnownProviders() []Provider {
+	return []Provider{Anthropic, MistralAI, OpenAI, HuggingfaceHub, Cohere, Google}
+}
+
+// ValidationProblem is a catalog validation finding located by its JSON path, eg "$.models[3].architecture".
+type ValidationProblem struct {
+	Path    string
+	Message string
+}
+
+func (p ValidationProblem) String() string {
+	return p.Path + ": " + p.Message
+}
+
+// ValidateCatalogFile reads a JSON or YAML catalog file and validates it with ValidateCatalog.
+func ValidateCatalogFile(source string, base *Registry) ([]ValidationProblem, error) {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if isYAML(source) {
+		data, err = yamlToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ValidateCatalog(data, base)
+}
+
+// ValidateCatalog reports every problem found in a JSON catalog: missing or unknown fields, providers that
+// are not Provider constants, missing or inconsistent architectures, duplicate models, aliases that do not
+// resolve and unknown warning codes. Aliases may point at models of base, eg the embedded catalog when
+// validating an overlay; base may be nil. An error is returned only when data is not valid JSON.
+func ValidateCatalog(data []byte, base *Registry) ([]ValidationProblem, error) {
+	var p fastjson.Parser
+	v, err := p.ParseBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	c := &catalogValidator{
+		base:  base,
+		names: make(map[modelKey]bool),
+	}
+	if v.Type() != fastjson.TypeObject {
+		c.report("$", "catalog must be an object")
+		return c.problems, nil
+	}
+	c.validateFields("$", v, "version", "aliases", "models")
+	if !v.Exists("models") {
+		c.report("$.models", "models are missing")
+	}
+	for i, model := range v.GetArray("models") {
+		c.validateModel(fmt.Sprintf("$.models[%d]", i), model)
+	}
+	for i, alias := range v.GetArray("aliases") {
+		c.validateAlias(fmt.Sprintf("$.aliases[%d]", i), alias)
+	}
+	c.validateAliasTargets(v.GetArray("aliases"))
+	return c.problems, nil
+}
+
+type catalogValidator struct {
+	base     *Registry
+	names    map[modelKey]bool
+	problems []ValidationProblem
+}
+
+func (c *catalogValidator) report(path, format string, args ...any) {
+	c.problems = append(c.problems, ValidationProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// validateFields reports the fields of object that are not in known, as unknown fields would be silently ignored
+// when the catalog is loaded.
+func (c *catalogValidator) validateFields(path string, object *fastjson.Value, known ...string) {
+	fields, err := object.Object()
+	if err != nil {
+		return
+	}
+	fields.Visit(func(key []byte, _ *fastjson.Value) {
+		if !slices.Contains(known, string(key)) {
+			c.report(path+"."+string(key), "unknown field %q", key)
+		}
+	})
+}
+
+func (c *catalogValidator) validateModel(path string, model *fastjson.Value) {
+	c.validateFields(path, model,
+		"type", "provider", "name", "precision", "task", "modalities", "architecture", "warnings", "sources")
+	name := string(model.GetStringBytes("name"))
+	provider := Provider(model.GetStringBytes("provider"))
+	if name == "" {
+		c.report(path+".name", "name is missing")
+	}
+	c.validateProvider(path+".provider", provider)
+
+	key := modelKey{provider: provider, name: name}
+	if name != "" && c.names[key] {
+		c.report(path+".name", "duplicate model %q for provider %q", name, provider)
+	}
+	c.names[key] = true
+
+	if _, err := ParsePrecision(string(model.GetStringBytes("precision"))); err != nil {
+		c.report(path+".precision", "%v", err)
+	}
+	task, err := ParseTask(string(model.GetStringBytes("task")))
+	if err != nil {
+		c.report(path+".task", "%v", err)
+	}
+	c.validateModalities(path+".modalities", task, model.Get("modalities"))
+	for i, warning := range model.GetArray("warnings") {
+		c.validateWarning(fmt.Sprintf("%s.warnings[%d]", path, i), warning)
+	}
+	c.validateArchitecture(path+".architecture", model.Get("architecture"))
+}
+
+func (c *catalogValidator) validateProvider(path string, provider Provider) {
+	if provider == "" {
+		c.report(path, "provider is missing")
+		return
+	}
+	if !slices.Contains(KnownProviders(), provider) {
+		c.report(path, "unknown provider %q", provider)
+	}
+}
+
+func (c *catalogValidator) validateModalities(path string, task Task, modalities *fastjson.Value) {
+	if modalities == nil {
+		return
+	}
+	if modalities.Type() != fastjson.TypeObject {
+		c.report(path, "modalities must be an object")
+		return
+	}
+	c.validateFields(path, modalities, "input", "output")
+	for _, direction := range []string{"input", "output"} {
+		values := modalities.GetArray(direction)
+		if len(values) == 0 {
+			c.report(path+"."+direction, "%s modalities are missing", direction)
+		}
+		for i, value := range values {
+			if _, err := ParseModality(string(value.GetStringBytes())); err != nil {
+				c.report(fmt.Sprintf("%s.%s[%d]", path, direction, i), "%v", err)
+			}
+		}
+	}
+	if task == TaskChat && !slices.ContainsFunc(modalities.GetArray("output"), func(v *fastjson.Value) bool {
+		return Modality(v.GetStringBytes()) == ModalityText
+	}) {
+		c.report(path+".output", "chat models must output text")
+	}
+}
+
+func (c *catalogValidator) validateWarning(path string, warning *fastjson.Value) {
+	code := string(warning.GetStringBytes())
+	if warning.Type() == fastjson.TypeObject {
+		c.validateFields(path, warning, "code", "message", "severity")
+		code = string(warning.GetStringBytes("code"))
+	}
+	if code == "" {
+		c.report(path, "warning code is missing")
+		return
+	}
+	if _, ok := LookupWarning(code); !ok {
+		c.report(path, "unknown warning code %q", code)
+	}
+}
+
+func (c *catalogValidator) validateArchitecture(path string, architecture *fastjson.Value) {
+	if architecture == nil || architecture.Type() == fastjson.TypeNull {
+		c.report(path, "architecture is missing")
+		return
+	}
+	c.validateFields(path, architecture, "type", "parameters")
+	c.validateParameterFields(path+".parameters", architecture.Get("parameters"))
+	architectureType := ArchitectureType(architecture.GetStringBytes("type"))
+	parameters, err := parseParameters(architectureType, architecture.Get("parameters"))
+	if err != nil {
+		c.report(path+".parameters", "%v", err)
+		return
+	}
+	if err := validateArchitecture(Architecture{Type: architectureType, Parameters: parameters}); err != nil {
+		c.report(path, "%v", err)
+	}
+}
+
+// validateParameterFields reports unknown fields of parameters, which is either a count, a range or an object of
+// total and active counts.
+func (c *catalogValidator) validateParameterFields(path string, parameters *fastjson.Value) {
+	if parameters == nil || parameters.Type() != fastjson.TypeObject {
+		return
+	}
+	if !parameters.Exists("total") && !parameters.Exists("active") {
+		c.validateFields(path, parameters, "min", "max")
+		return
+	}
+	c.validateFields(path, parameters, "total", "active")
+	for _, field := range []string{"total", "active"} {
+		if count := parameters.Get(field); count != nil && count.Type() == fastjson.TypeObject {
+			c.validateFields(path+"."+field, count, "min", "max")
+		}
+	}
+}
+
+func (c *catalogValidator) validateAlias(path string, alias *fastjson.Value) {
+	c.validateFields(path, alias, "type", "provider", "name", "alias", "precision")
+	if len(alias.GetStringBytes("name")) == 0 {
+		c.report(path+".name", "alias name is missing")
+	}
+	if len(alias.GetStringBytes("alias")) == 0 {
+		c.report(path+".alias", "alias target is missing")
+	}
+	if provider := Provider(alias.GetStringBytes("provider")); provider != "" {
+		c.validateProvider(path+".provider", provider)
+	}
+	if _, err := ParsePrecision(string(alias.GetStringBytes("precision"))); err != nil {
+		c.report(path+".precision", "%v", err)
+	}
+}
+
+// validateAliasTargets reports aliases that do not resolve to a model of the catalog or of the base registry.
+func (c *catalogValidator) validateAliasTargets(aliases []*fastjson.Value) {
+	r := NewRegistry(nil)
+	if c.base != nil {
+		r = c.base.clone()
+	}
+	for key := range c.names {
+		r.models[key] = AIModel{name: key.name, provider: key.provider}
+		r.byName[key.name] = key
+	}
+	for _, alias := range aliases {
+		r.addAlias(Alias{
+			ProviderName: string(alias.GetStringBytes("provider")),
+			ModelName:    string(alias.GetStringBytes("name")),
+			ModelAlias:   string(alias.GetStringBytes("alias")),
+		})
+	}
+
+	for i, alias := range aliases {
+		name := string(alias.GetStringBytes("name"))
+		if name == "" || len(alias.GetStringBytes("alias")) == 0 {
+			continue
+		}
+		provider := Provider(alias.GetStringBytes("provider"))
+		_, err := r.resolve(modelKey{provider: provider, name: name})
+		switch {
+		case errors.Is(err, errAliasCycle):
+			c.report(fmt.Sprintf("$.aliases[%d].alias", i), "alias %q is part of a cycle", name)
+		case err != nil:
+			c.report(fmt.Sprintf("$.aliases[%d].alias", i), "alias %q does not resolve to a model", name)
+		}
+	}
+}
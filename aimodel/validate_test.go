@@ -0,0 +1,175 @@
+package aimodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCatalog(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsonContent string
+		base        *Registry
+		expectError bool
+		expected    []ValidationProblem
+	}{
+		{
+			name: "returns no problems for valid catalog",
+			jsonContent: `{
+				"aliases": [{"provider": "openai", "name": "gpt-4-deployment", "alias": "gpt-4"}],
+				"models": [
+					{
+						"provider": "openai",
+						"name": "gpt-4",
+						"architecture": {"type": "moe", "parameters": {"total": 1760, "active": {"min": 220, "max": 880}}},
+						"warnings": ["model-arch-not-released"]
+					}
+				]
+			}`,
+		},
+		{
+			name: "reports every problem with its path",
+			jsonContent: `{
+				"aliases": [
+					{"provider": "openai", "name": "gpt-5", "alias": "gpt-missing"},
+					{"provider": "openai", "name": "a", "alias": "b"},
+					{"provider": "openai", "name": "b", "alias": "a"}
+				],
+				"models": [
+					{
+						"provider": "acme",
+						"name": "acme-chat",
+						"architecture": {"type": "dense", "parameters": 7}
+					},
+					{
+						"provider": "openai",
+						"name": "gpt-4"
+					},
+					{
+						"provider": "openai",
+						"name": "gpt-4",
+						"architecture": {"type": "moe", "parameters": {"total": 1760}},
+						"warnings": ["model-arch-unknown", {"code": "model-arch-multimodal"}]
+					},
+					{
+						"provider": "openai",
+						"name": "gpt-4o",
+						"precision": "fp12",
+						"architecture": {"type": "dense", "parameters": {"min": 30, "max": 10}}
+					}
+				]
+			}`,
+			expected: []ValidationProblem{
+				{Path: "$.models[0].provider", Message: `unknown provider "acme"`},
+				{Path: "$.models[1].architecture", Message: "architecture is missing"},
+				{Path: "$.models[2].name", Message: `duplicate model "gpt-4" for provider "openai"`},
+				{Path: "$.models[2].warnings[0]", Message: `unknown warning code "model-arch-unknown"`},
+				{Path: "$.models[2].architecture", Message: "moe model is missing active parameters"},
+				{Path: "$.models[3].precision", Message: `unknown precision "fp12"`},
+				{
+					Path:    "$.models[3].architecture",
+					Message: "invalid total parameters: min (30) is greater than max (10)",
+				},
+				{Path: "$.aliases[0].alias", Message: `alias "gpt-5" does not resolve to a model`},
+				{Path: "$.aliases[1].alias", Message: `alias "a" is part of a cycle`},
+				{Path: "$.aliases[2].alias", Message: `alias "b" is part of a cycle`},
+			},
+		},
+		{
+			name: "reports unknown tasks and modalities",
+			jsonContent: `{
+				"models": [
+					{
+						"provider": "openai",
+						"name": "text-embedding-3-small",
+						"task": "embedding",
+						"architecture": {"type": "dense", "parameters": 1}
+					},
+					{
+						"provider": "openai",
+						"name": "gpt-4o",
+						"modalities": {"input": ["text", "video"], "output": ["image"]},
+						"architecture": {"type": "dense", "parameters": 200}
+					}
+				]
+			}`,
+			expected: []ValidationProblem{
+				{Path: "$.models[0].task", Message: `unknown task "embedding"`},
+				{Path: "$.models[1].modalities.input[1]", Message: `unknown modality "video"`},
+				{Path: "$.models[1].modalities.output", Message: "chat models must output text"},
+			},
+		},
+		{
+			name: "reports unknown fields",
+			jsonContent: `{
+				"extra": true,
+				"aliases": [{"provider": "openai", "name": "gpt-4-deployment", "alias": "gpt-4", "typo": 1}],
+				"models": [
+					{
+						"provider": "openai",
+						"name": "gpt-4",
+						"architecture": {
+							"type": "moe",
+							"parameters": {"total": {"min": 1000, "maks": 2000}, "active": 220},
+							"layers": 120
+						},
+						"modalities": {"input": ["text"], "output": ["text"], "inputs": ["image"]},
+						"warnigs": ["model-arch-not-released"],
+						"warnings": [{"code": "model-arch-not-released", "level": "info"}],
+						"sorces": ["https://example.com"]
+					}
+				]
+			}`,
+			expected: []ValidationProblem{
+				{Path: "$.extra", Message: `unknown field "extra"`},
+				{Path: "$.models[0].warnigs", Message: `unknown field "warnigs"`},
+				{Path: "$.models[0].sorces", Message: `unknown field "sorces"`},
+				{Path: "$.models[0].modalities.inputs", Message: `unknown field "inputs"`},
+				{Path: "$.models[0].warnings[0].level", Message: `unknown field "level"`},
+				{Path: "$.models[0].architecture.layers", Message: `unknown field "layers"`},
+				{Path: "$.models[0].architecture.parameters.total.maks", Message: `unknown field "maks"`},
+				{Path: "$.aliases[0].typo", Message: `unknown field "typo"`},
+			},
+		},
+		{
+			name: "resolves overlay aliases against the base registry",
+			jsonContent: `{
+				"aliases": [{"provider": "openai", "name": "prod-gpt", "alias": "gpt-4"}],
+				"models": []
+			}`,
+			base: testRegistry(),
+		},
+		{
+			name:        "reports missing models",
+			jsonContent: `{}`,
+			expected:    []ValidationProblem{{Path: "$.models", Message: "models are missing"}},
+		},
+		{
+			name:        "returns error for malformed JSON",
+			jsonContent: `{"models": [`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, err := ValidateCatalog([]byte(tt.jsonContent), tt.base)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, problems)
+		})
+	}
+}
+
+func TestValidateCatalog_Embedded(t *testing.T) {
+	data, err := embeddedCatalog.ReadFile(embeddedCatalogPath)
+	assert.NoError(t, err)
+
+	problems, err := ValidateCatalog(data, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
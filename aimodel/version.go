@@ -0,0 +1,49 @@
+package aimodel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// CatalogVersion identifies the catalog a model was loaded from, so that estimates can be traced back to
+// the model data that produced them.
+type CatalogVersion struct {
+	// Version is the version declared by the catalog file.
+	Version string
+	// Hash is the SHA-256 of the canonical encoding of the catalog content, excluding its version.
+	Hash string
+}
+
+func (v CatalogVersion) String() string {
+	if v.Version == "" {
+		return v.Hash
+	}
+	return v.Version + "+" + v.Hash
+}
+
+// EmbeddedCatalogVersion returns the version of the catalog compiled into the binary, as loaded by
+// DefaultRegistry. Overlays loaded into the default registry do not change it.
+func EmbeddedCatalogVersion() (CatalogVersion, error) {
+	if _, err := DefaultRegistry(); err != nil {
+		return CatalogVersion{}, err
+	}
+	return defaultCatalogVersion, nil
+}
+
+// CatalogVersion returns the declared version and content hash of the catalog.
+func (d *ModelData) CatalogVersion() CatalogVersion {
+	return CatalogVersion{Version: d.Version, Hash: catalogHash(d)}
+}
+
+// catalogHash returns the SHA-256 of the canonical encoding of data without its version, so that
+// formatting and ordering changes do not change the hash.
+func catalogHash(data *ModelData) string {
+	unversioned := *data
+	unversioned.Version = ""
+	encoded, err := EncodeCatalog(&unversioned)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
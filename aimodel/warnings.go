@@ -0,0 +1,105 @@
+package aimodel
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// Severity ranks how much a warning affects the confidence of an impact estimate.
+type Severity string
+
+const (
+	// SeverityInfo warnings are informational and do not change the confidence of an estimate.
+	SeverityInfo Severity = "info"
+	// SeverityLowConfidence warnings mean the estimate relies on assumptions and should be flagged as such.
+	SeverityLowConfidence Severity = "low-confidence"
+)
+
+// Known warning codes used in the catalog and attached by this package.
+const (
+	WarningModelArchNotReleased = "model-arch-not-released"
+	WarningModelArchMultimodal  = "model-arch-multimodal"
+	WarningModelNameFuzzyMatch  = "model-name-fuzzy-match"
+	WarningModelParamsEstimated = "model-params-estimated"
+)
+
+// WarningDefinition describes a known warning code.
+type WarningDefinition struct {
+	Code     string
+	Message  string
+	Severity Severity
+}
+
+var (
+	warningDefinitionsMu sync.RWMutex
+	warningDefinitions   = map[string]WarningDefinition{
+		WarningModelArchNotReleased: {
+			Code:     WarningModelArchNotReleased,
+			Message:  "The model architecture has not been released, expect lower precision.",
+			Severity: SeverityLowConfidence,
+		},
+		WarningModelArchMultimodal: {
+			Code:     WarningModelArchMultimodal,
+			Message:  "The model architecture is multimodal, expect lower precision.",
+			Severity: SeverityLowConfidence,
+		},
+		WarningModelParamsEstimated: {
+			Code:     WarningModelParamsEstimated,
+			Message:  "The parameter count of the model is not published and was estimated, expect lower precision.",
+			Severity: SeverityLowConfidence,
+		},
+		WarningModelNameFuzzyMatch: {
+			Code:     WarningModelNameFuzzyMatch,
+			Message:  "The requested model name was matched to a catalog entry with a different name.",
+			Severity: SeverityInfo,
+		},
+	}
+)
+
+// warningCodePattern is the format of warning codes, which the catalog JSON Schema also enforces.
+var warningCodePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// RegisterWarning adds or replaces a known warning code, eg for codes used in overlay catalogs.
+// Codes are in kebab case, eg "model-fine-tuned", so that overlays using them also match the catalog JSON Schema.
+func RegisterWarning(definition WarningDefinition) error {
+	if definition.Code == "" {
+		return errors.New("warning code cannot be empty")
+	}
+	if !warningCodePattern.MatchString(definition.Code) {
+		return fmt.Errorf("warning code %q must be in kebab case", definition.Code)
+	}
+	warningDefinitionsMu.Lock()
+	defer warningDefinitionsMu.Unlock()
+	warningDefinitions[definition.Code] = definition
+	return nil
+}
+
+// LookupWarning returns the definition of a known warning code.
+func LookupWarning(code string) (WarningDefinition, bool) {
+	warningDefinitionsMu.RLock()
+	defer warningDefinitionsMu.RUnlock()
+	definition, ok := warningDefinitions[code]
+	return definition, ok
+}
+
+// NewWarning returns the warning for code, with the message and severity of the known definition.
+// Unknown codes are kept with the code as message and no severity.
+func NewWarning(code string) Warning {
+	definition, ok := LookupWarning(code)
+	if !ok {
+		return Warning{Code: code, Message: code}
+	}
+	return Warning{Code: code, Message: definition.Message, Severity: definition.Severity}
+}
+
+// LowConfidence reports whether any of the warnings lowers the confidence of an estimate.
+func LowConfidence(warnings []Warning) bool {
+	for _, warning := range warnings {
+		if warning.Severity == SeverityLowConfidence {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package carbonintensity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/omegabytes/ecologits-go/request"
+)
+
+// ElectricityMapsBaseURL is the base URL of the Electricity Maps API.
+const ElectricityMapsBaseURL = "https://api.electricitymap.org/v3"
+
+// electricityMapsZones maps the regions of the electricity mix dataset to Electricity Maps zones. Countries and
+// regions missing from the map use their code as is, eg "FR" or "CA-QC".
+var electricityMapsZones = map[string]string{
+	"US-BPA":   "US-NW-BPAT",
+	"US-CAISO": "US-CAL-CISO",
+	"US-ERCOT": "US-TEX-ERCO",
+	"US-ISONE": "US-NE-ISNE",
+	"US-MISO":  "US-MIDW-MISO",
+	"US-NYISO": "US-NY-NYIS",
+	"US-PJM":   "US-MIDA-PJM",
+	"US-SOCO":  "US-SE-SOCO",
+	"US-SPP":   "US-CENT-SWPP",
+}
+
+// ElectricityMaps is a Source backed by the Electricity Maps carbon intensity API, which publishes life-cycle
+// average carbon intensities for countries and grid zones.
+type ElectricityMaps struct {
+	APIKey     string
+	BaseURL    string
+	HTTPClient *http.Client
+	// Zones maps geos to Electricity Maps zones, in addition to the built-in mapping, eg {"US-CAISO": "US-CAL-CISO"}.
+	Zones map[string]string
+}
+
+// NewElectricityMaps returns an Electricity Maps source authenticated with apiKey.
+func NewElectricityMaps(apiKey string) *ElectricityMaps {
+	return &ElectricityMaps{APIKey: apiKey, BaseURL: ElectricityMapsBaseURL}
+}
+
+type electricityMapsResponse struct {
+	Zone            string   `json:"zone"`
+	CarbonIntensity *float64 `json:"carbonIntensity"`
+	Datetime        string   `json:"datetime"`
+}
+
+// CarbonIntensity returns the carbon intensity of geo during the hour that contains at, or the latest one.
+func (e *ElectricityMaps) CarbonIntensity(ctx context.Context, geo string, at time.Time) (Intensity, error) {
+	normalized := request.NormalizeGeo(geo)
+	zone, err := e.zone(normalized)
+	if err != nil {
+		return Intensity{}, err
+	}
+
+	query := url.Values{"zone": {zone}}
+	endpoint := "/carbon-intensity/latest"
+	if !at.IsZero() {
+		endpoint = "/carbon-intensity/past"
+		query.Set("datetime", at.UTC().Truncate(time.Hour).Format(time.RFC3339))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.BaseURL+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return Intensity{}, err
+	}
+	req.Header.Set("auth-token", e.APIKey)
+
+	var resp electricityMapsResponse
+	if err := doJSON(e.HTTPClient, req, &resp); err != nil {
+		return Intensity{}, err
+	}
+	if resp.CarbonIntensity == nil {
+		return Intensity{}, fmt.Errorf("no carbon intensity for zone %q", zone)
+	}
+	measuredAt, err := time.Parse(time.RFC3339, resp.Datetime)
+	if err != nil {
+		return Intensity{}, fmt.Errorf("invalid datetime: %w", err)
+	}
+	// Electricity Maps publishes intensities in gCO2eq/kWh.
+	return Intensity{Geo: normalized, GWP: *resp.CarbonIntensity / 1000, At: measuredAt}, nil
+}
+
+// zone returns the Electricity Maps zone of a normalized geo. Regions are already prefixed with the alpha-2
+// code of their country, which Electricity Maps uses for countries too.
+func (e *ElectricityMaps) zone(geo string) (string, error) {
+	if zone, ok := e.Zones[geo]; ok {
+		return zone, nil
+	}
+	if zone, ok := electricityMapsZones[geo]; ok {
+		return zone, nil
+	}
+	if strings.Contains(geo, "-") {
+		return geo, nil
+	}
+	country, ok := request.Alpha2Code(geo)
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedGeo, geo)
+	}
+	return country, nil
+}
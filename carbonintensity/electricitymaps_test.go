@@ -0,0 +1,91 @@
+package carbonintensity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElectricityMaps_CarbonIntensity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("auth-token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid token"}`)
+			return
+		}
+		zone := r.URL.Query().Get("zone")
+		switch {
+		case r.URL.Path == "/carbon-intensity/latest" && zone == "FR":
+			fmt.Fprint(w, `{"zone":"FR","carbonIntensity":35,"datetime":"2025-01-15T10:00:00.000Z"}`)
+		case r.URL.Path == "/carbon-intensity/past" && zone == "US-CAL-CISO" &&
+			r.URL.Query().Get("datetime") == "2025-01-15T10:00:00Z":
+			fmt.Fprint(w, `{"zone":"US-CAL-CISO","carbonIntensity":250,"datetime":"2025-01-15T10:00:00.000Z"}`)
+		case zone == "CA-QC":
+			fmt.Fprint(w, `{"zone":"CA-QC","carbonIntensity":null,"datetime":"2025-01-15T10:00:00.000Z"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"zone not found"}`)
+		}
+	}))
+	defer server.Close()
+	hour := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		apiKey        string
+		geo           string
+		at            time.Time
+		want          Intensity
+		expectedError string
+	}{
+		{
+			name:   "should return the latest intensity of countries",
+			apiKey: "secret",
+			geo:    "FRA",
+			want:   Intensity{Geo: "FRA", GWP: 0.035, At: hour},
+		},
+		{
+			name:   "should return past intensities of mapped regions",
+			apiKey: "secret",
+			geo:    "US-CAISO",
+			at:     hour.Add(20 * time.Minute),
+			want:   Intensity{Geo: "US-CAISO", GWP: 0.25, At: hour},
+		},
+		{
+			name:          "should reject missing intensities",
+			apiKey:        "secret",
+			geo:           "CA-QC",
+			expectedError: `no carbon intensity for zone "CA-QC"`,
+		},
+		{
+			name:          "should report API errors",
+			apiKey:        "wrong",
+			geo:           "FR",
+			expectedError: `unexpected status 401: {"error":"invalid token"}`,
+		},
+		{
+			name:          "should reject geos without a zone",
+			apiKey:        "secret",
+			geo:           "",
+			expectedError: `unsupported geo: "WORLD"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewElectricityMaps(tt.apiKey)
+			source.BaseURL = server.URL
+			got, err := source.CarbonIntensity(context.Background(), tt.geo, tt.at)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Package carbonintensity provides request.ElectricityMixProvider implementations backed by live carbon intensity
+APIs: Electricity Maps, WattTime and the UK Carbon Intensity API.
+
+The package is optional. The core library only uses the static datasets embedded in the request package and
+never makes network calls unless a Provider from this package is passed to impact.ComputeImpactsWithMixProvider.
+*/
+package carbonintensity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/omegabytes/ecologits-go/request"
+)
+
+const (
+	defaultTimeout  = 5 * time.Second
+	defaultCacheTTL = time.Hour
+	// defaultRateLimit and defaultRateInterval keep clients within the free tiers of the supported APIs.
+	defaultRateLimit    = 30
+	defaultRateInterval = time.Minute
+	// maxCacheEntries bounds the cache, past which expired entries are evicted, then the entries that expire first.
+	maxCacheEntries = 10000
+)
+
+var (
+	// ErrUnsupportedGeo is returned by a Source that has no data for a geo.
+	ErrUnsupportedGeo = errors.New("unsupported geo")
+	// ErrRateLimited is returned when a Provider skips a call to its Source to stay within its rate limit.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Intensity is the carbon intensity of a geo over an interval.
+type Intensity struct {
+	// Geo is the geo the Source reported, eg "FRA".
+	Geo string
+	// GWP is the carbon intensity in kgCO2eq/kWh.
+	GWP float64
+	// At is the start of the interval the intensity was measured or forecast over.
+	At time.Time
+	// Marginal reports whether GWP is a marginal intensity rather than the average intensity of the mix.
+	Marginal bool
+}
+
+// Source fetches carbon intensities from an API. A zero time asks for the latest intensity.
+type Source interface {
+	CarbonIntensity(ctx context.Context, geo string, at time.Time) (Intensity, error)
+}
+
+// Option configures a Provider.
+type Option func(*Provider)
+
+// WithFallback sets the provider of the mixes used when the Source fails and of the ADPe and PE factors, which
+// carbon intensity APIs do not publish. It defaults to the static dataset of the request package, and must hold
+// the world mix for the geos it does not know.
+func WithFallback(fallback request.ElectricityMixProvider) Option {
+	return func(p *Provider) { p.fallback = fallback }
+}
+
+// WithTimeout bounds the duration of each call to the Source.
+func WithTimeout(timeout time.Duration) Option {
+	return func(p *Provider) { p.timeout = timeout }
+}
+
+// WithCacheTTL sets how long intensities are cached. Cache entries are keyed by geo and hour.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(p *Provider) { p.cacheTTL = ttl }
+}
+
+// WithRateLimit allows at most requests calls to the Source per interval. Lookups past the limit use the
+// fallback.
+func WithRateLimit(requests int, interval time.Duration) Option {
+	return func(p *Provider) { p.limiter = newLimiter(requests, interval) }
+}
+
+// WithStrict makes lookups return the error of the Source instead of using the fallback.
+func WithStrict() Option {
+	return func(p *Provider) { p.strict = true }
+}
+
+// Provider is a request.ElectricityMixProvider that takes the GWP factor of the electricity mix from a Source and
+// the other factors from a static fallback. It caches intensities, rate limits and times out calls to the
+// Source, and uses the fallback mix when the Source fails, so that estimates never depend on the API being up.
+type Provider struct {
+	source   Source
+	fallback request.ElectricityMixProvider
+	timeout  time.Duration
+	cacheTTL time.Duration
+	limiter  *limiter
+	strict   bool
+	now      func() time.Time
+
+	maxEntries int
+
+	mu    sync.Mutex
+	cache map[cacheKey]cacheEntry
+}
+
+type cacheKey struct {
+	geo  string
+	hour int64
+}
+
+type cacheEntry struct {
+	intensity Intensity
+	expires   time.Time
+}
+
+// NewProvider returns a Provider that fetches intensities from source.
+func NewProvider(source Source, opts ...Option) (*Provider, error) {
+	if source == nil {
+		return nil, errors.New("source cannot be nil")
+	}
+	p := &Provider{
+		source:   source,
+		timeout:  defaultTimeout,
+		cacheTTL: defaultCacheTTL,
+		limiter:  newLimiter(defaultRateLimit, defaultRateInterval),
+		now:      time.Now,
+		cache:    make(map[cacheKey]cacheEntry),
+
+		maxEntries: maxCacheEntries,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.fallback == nil {
+		database, err := request.DefaultMixDatabase()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get fallback electricity mixes: %w", err)
+		}
+		p.fallback = database
+	}
+	p.limiter.now = p.now
+	return p, nil
+}
+
+// ElectricityMix returns the mix of geo at a point in time, or the latest mix for a zero time. The GWP factor
+// comes from the Source, and Hour is set to the start of the interval it applies to. The ADPe and PE factors come
+// from the fallback mix of geo, or of its country or the world when the fallback does not know geo, in which case
+// FactorsGeo is set to the geo they come from. When the Source fails, it returns that fallback mix with
+// SourceError set, whose Hour is zero for static averages.
+func (p *Provider) ElectricityMix(ctx context.Context, geo string, at time.Time) (request.ElectricityMix, error) {
+	intensity, err := p.intensity(ctx, geo, at)
+	if err != nil {
+		if p.strict {
+			return request.ElectricityMix{}, err
+		}
+		average, fallbackErr := p.staticMix(ctx, geo, at)
+		if fallbackErr != nil {
+			return request.ElectricityMix{}, fallbackErr
+		}
+		average.SourceError = err.Error()
+		return average, nil
+	}
+
+	mix, err := p.staticMix(ctx, geo, at)
+	if err != nil {
+		return request.ElectricityMix{}, err
+	}
+	if mix.Fallback() {
+		mix.FactorsGeo = mix.Geo
+		mix.Geo = mix.RequestedGeo
+	}
+	mix.GWP = intensity.GWP
+	mix.Hour = intensity.At.UTC()
+	mix.Marginal = intensity.Marginal
+	return mix, nil
+}
+
+// staticMix returns the fallback mix of geo, or the world mix with RequestedGeo set to geo when the fallback does
+// not know geo nor its country.
+func (p *Provider) staticMix(ctx context.Context, geo string, at time.Time) (request.ElectricityMix, error) {
+	mix, err := p.fallback.ElectricityMix(ctx, geo, at)
+	if !errors.Is(err, request.ErrUnknownGeo) {
+		return mix, err
+	}
+	mix, err = p.fallback.ElectricityMix(ctx, request.WorldGeo, at)
+	if err != nil {
+		return request.ElectricityMix{}, err
+	}
+	mix.RequestedGeo = request.NormalizeGeo(geo)
+	return mix, nil
+}
+
+// intensity returns the cached intensity of geo at a point in time, or fetches it from the Source.
+func (p *Provider) intensity(ctx context.Context, geo string, at time.Time) (Intensity, error) {
+	now := p.now()
+	hour := at
+	if hour.IsZero() {
+		hour = now
+	}
+	key := cacheKey{geo: request.NormalizeGeo(geo), hour: hour.UTC().Truncate(time.Hour).Unix()}
+
+	p.mu.Lock()
+	entry, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.intensity, nil
+	}
+
+	if !p.limiter.allow() {
+		return Intensity{}, ErrRateLimited
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	intensity, err := p.source.CarbonIntensity(ctx, geo, at)
+	if err != nil {
+		return Intensity{}, fmt.Errorf("failed to get carbon intensity of %q: %w", geo, err)
+	}
+	if intensity.GWP < 0 {
+		return Intensity{}, fmt.Errorf("carbon intensity of %q cannot be negative: %g", geo, intensity.GWP)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.cache[key]; !ok && len(p.cache) >= p.maxEntries {
+		p.evict(now)
+	}
+	p.cache[key] = cacheEntry{intensity: intensity, expires: now.Add(p.cacheTTL)}
+	return intensity, nil
+}
+
+// evict removes the expired cache entries, then the entries that expire first until there is room for a new one.
+// It must be called with mu held.
+func (p *Provider) evict(now time.Time) {
+	for k, e := range p.cache {
+		if !now.Before(e.expires) {
+			delete(p.cache, k)
+		}
+	}
+	if len(p.cache) < p.maxEntries {
+		return
+	}
+	keys := slices.SortedFunc(maps.Keys(p.cache), func(a, b cacheKey) int {
+		return p.cache[a].expires.Compare(p.cache[b].expires)
+	})
+	for _, k := range keys[:len(keys)-p.maxEntries+1] {
+		delete(p.cache, k)
+	}
+}
+
+// limiter is a token bucket that allows bursts of up to max calls and refills at max calls per interval.
+type limiter struct {
+	mu       sync.Mutex
+	tokens   float64
+	max      float64
+	interval time.Duration
+	last     time.Time
+	now      func() time.Time
+}
+
+func newLimiter(requests int, interval time.Duration) *limiter {
+	return &limiter{tokens: float64(requests), max: float64(requests), interval: interval, now: time.Now}
+}
+
+// allow reports whether a call may be made now, and consumes a token if so.
+func (l *limiter) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if !l.last.IsZero() && l.interval > 0 {
+		l.tokens = min(l.max, l.tokens+l.max*float64(now.Sub(l.last))/float64(l.interval))
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
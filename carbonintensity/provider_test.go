@@ -0,0 +1,236 @@
+package carbonintensity
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/omegabytes/ecologits-go/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sourceFunc adapts a function to the Source interface.
+type sourceFunc func(ctx context.Context, geo string, at time.Time) (Intensity, error)
+
+func (f sourceFunc) CarbonIntensity(ctx context.Context, geo string, at time.Time) (Intensity, error) {
+	return f(ctx, geo, at)
+}
+
+func TestProvider_ElectricityMix(t *testing.T) {
+	hour := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	fallback := request.NewMixDatabase([]request.ElectricityMix{
+		{Geo: "FRA", ADPe: 4e-08, GWP: 0.08, PE: 12},
+		{Geo: request.WorldGeo, ADPe: 7e-08, GWP: 0.59, PE: 10},
+	})
+	average := func(sourceError string) request.ElectricityMix {
+		return request.ElectricityMix{
+			Geo: "FRA", RequestedGeo: "FRA", ADPe: 4e-08, GWP: 0.08, PE: 12, SourceError: sourceError,
+		}
+	}
+	live := sourceFunc(func(_ context.Context, geo string, _ time.Time) (Intensity, error) {
+		return Intensity{Geo: request.NormalizeGeo(geo), GWP: 0.035, At: hour}, nil
+	})
+	marginal := sourceFunc(func(_ context.Context, geo string, _ time.Time) (Intensity, error) {
+		return Intensity{Geo: request.NormalizeGeo(geo), GWP: 0.2, At: hour, Marginal: true}, nil
+	})
+	failing := sourceFunc(func(context.Context, string, time.Time) (Intensity, error) {
+		return Intensity{}, errors.New("service unavailable")
+	})
+	blocking := sourceFunc(func(ctx context.Context, _ string, _ time.Time) (Intensity, error) {
+		<-ctx.Done()
+		return Intensity{}, ctx.Err()
+	})
+
+	tests := []struct {
+		name          string
+		source        Source
+		opts          []Option
+		geo           string
+		want          request.ElectricityMix
+		expectedError string
+	}{
+		{
+			name:   "should combine the live GWP with the fallback factors",
+			source: live,
+			geo:    "FR",
+			want:   request.ElectricityMix{Geo: "FRA", RequestedGeo: "FRA", ADPe: 4e-08, GWP: 0.035, PE: 12, Hour: hour},
+		},
+		{
+			name:   "should mark marginal intensities",
+			source: marginal,
+			geo:    "FR",
+			want: request.ElectricityMix{
+				Geo: "FRA", RequestedGeo: "FRA", ADPe: 4e-08, GWP: 0.2, PE: 12, Hour: hour, Marginal: true,
+			},
+		},
+		{
+			name:   "should take the world factors for geos unknown to the fallback",
+			source: live,
+			geo:    "DE",
+			want: request.ElectricityMix{
+				Geo: "DEU", RequestedGeo: "DEU", FactorsGeo: request.WorldGeo, ADPe: 7e-08, GWP: 0.035, PE: 10,
+				Hour: hour,
+			},
+		},
+		{
+			name:   "should take the country factors for regions unknown to the fallback",
+			source: live,
+			geo:    "FR-IDF",
+			want: request.ElectricityMix{
+				Geo: "FR-IDF", RequestedGeo: "FR-IDF", FactorsGeo: "FRA", ADPe: 4e-08, GWP: 0.035, PE: 12, Hour: hour,
+			},
+		},
+		{
+			name:   "should fall back and record the error when the source fails",
+			source: failing,
+			geo:    "FR",
+			want:   average(`failed to get carbon intensity of "FR": service unavailable`),
+		},
+		{
+			name:          "should return source errors in strict mode",
+			source:        failing,
+			opts:          []Option{WithStrict()},
+			geo:           "FR",
+			expectedError: `failed to get carbon intensity of "FR": service unavailable`,
+		},
+		{
+			name:   "should fall back when the source times out",
+			source: blocking,
+			opts:   []Option{WithTimeout(10 * time.Millisecond)},
+			geo:    "FR",
+			want:   average(`failed to get carbon intensity of "FR": context deadline exceeded`),
+		},
+		{
+			name:          "should return timeouts in strict mode",
+			source:        blocking,
+			opts:          []Option{WithTimeout(10 * time.Millisecond), WithStrict()},
+			geo:           "FR",
+			expectedError: `failed to get carbon intensity of "FR": context deadline exceeded`,
+		},
+		{
+			name:   "should fall back to the world mix for geos unknown to the fallback when the source fails",
+			source: failing,
+			geo:    "GB",
+			want: request.ElectricityMix{
+				Geo: request.WorldGeo, RequestedGeo: "GBR", ADPe: 7e-08, GWP: 0.59, PE: 10,
+				SourceError: `failed to get carbon intensity of "GB": service unavailable`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewProvider(tt.source, append([]Option{WithFallback(fallback)}, tt.opts...)...)
+			require.NoError(t, err)
+
+			got, err := p.ElectricityMix(context.Background(), tt.geo, hour.Add(10*time.Minute))
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProvider_Cache(t *testing.T) {
+	var calls atomic.Int32
+	source := sourceFunc(func(_ context.Context, geo string, at time.Time) (Intensity, error) {
+		calls.Add(1)
+		return Intensity{Geo: geo, GWP: 0.1, At: at}, nil
+	})
+	p, err := NewProvider(source, WithCacheTTL(time.Hour))
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 15, 10, 5, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, geo := range []string{"FR", "FRA", "fr"} {
+		_, err := p.ElectricityMix(ctx, geo, time.Time{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), calls.Load(), "should cache by normalized geo")
+
+	_, err = p.ElectricityMix(ctx, "FR", now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load(), "should cache by hour")
+
+	now = now.Add(time.Hour)
+	_, err = p.ElectricityMix(ctx, "FR", now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load(), "should expire entries")
+}
+
+func TestProvider_CacheEviction(t *testing.T) {
+	var calls atomic.Int32
+	source := sourceFunc(func(_ context.Context, geo string, at time.Time) (Intensity, error) {
+		calls.Add(1)
+		return Intensity{Geo: geo, GWP: 0.1, At: at}, nil
+	})
+	p, err := NewProvider(source, WithCacheTTL(time.Hour), WithRateLimit(100, time.Minute))
+	require.NoError(t, err)
+	p.maxEntries = 2
+	now := time.Date(2025, 1, 15, 10, 5, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+	ctx := context.Background()
+	lookup := func(geo string) {
+		_, err := p.ElectricityMix(ctx, geo, time.Time{})
+		require.NoError(t, err)
+	}
+
+	lookup("FR")
+	now = now.Add(time.Minute)
+	lookup("US")
+	now = now.Add(time.Minute)
+	lookup(request.WorldGeo)
+	assert.Len(t, p.cache, 2, "should evict unexpired entries when full")
+	assert.Equal(t, int32(3), calls.Load())
+
+	lookup("US")
+	lookup(request.WorldGeo)
+	assert.Equal(t, int32(3), calls.Load(), "should keep the newest entries")
+	lookup("FR")
+	assert.Equal(t, int32(4), calls.Load(), "should evict the entry that expires first")
+}
+
+func TestProvider_RateLimit(t *testing.T) {
+	var calls atomic.Int32
+	source := sourceFunc(func(_ context.Context, geo string, _ time.Time) (Intensity, error) {
+		calls.Add(1)
+		return Intensity{Geo: geo, GWP: 0.1}, nil
+	})
+	p, err := NewProvider(source, WithRateLimit(2, time.Minute))
+	require.NoError(t, err)
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	p.limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, geo := range []string{"FR", "US"} {
+		_, err := p.ElectricityMix(ctx, geo, time.Time{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), calls.Load())
+
+	got, err := p.ElectricityMix(ctx, request.WorldGeo, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 0.590478, got.GWP, "should use the fallback past the limit")
+	assert.Equal(t, `rate limited`, got.SourceError)
+
+	now = now.Add(30 * time.Second)
+	_, err = p.ElectricityMix(ctx, request.WorldGeo, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load(), "should refill over time")
+
+	strict, err := NewProvider(source, WithRateLimit(0, time.Minute), WithStrict())
+	require.NoError(t, err)
+	_, err = strict.ElectricityMix(ctx, "FR", time.Time{})
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestNewProvider(t *testing.T) {
+	_, err := NewProvider(nil)
+	assert.EqualError(t, err, "source cannot be nil")
+}
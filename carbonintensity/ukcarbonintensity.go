@@ -0,0 +1,82 @@
+package carbonintensity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/omegabytes/ecologits-go/request"
+)
+
+const (
+	// UKCarbonIntensityBaseURL is the base URL of the UK Carbon Intensity API of the National Energy System
+	// Operator.
+	UKCarbonIntensityBaseURL = "https://api.carbonintensity.org.uk"
+	// ukCarbonIntensityTimeLayout is the datetime format of the UK Carbon Intensity API.
+	ukCarbonIntensityTimeLayout = "2006-01-02T15:04Z"
+	ukGeo                       = "GBR"
+)
+
+// UKCarbonIntensity is a Source backed by the UK Carbon Intensity API, which publishes the national carbon
+// intensity of Great Britain per half hour. It needs no API key. Regions of the United Kingdom use the national
+// intensity.
+type UKCarbonIntensity struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewUKCarbonIntensity returns a UK Carbon Intensity source.
+func NewUKCarbonIntensity() *UKCarbonIntensity {
+	return &UKCarbonIntensity{BaseURL: UKCarbonIntensityBaseURL}
+}
+
+type ukCarbonIntensityResponse struct {
+	Data []struct {
+		From      string `json:"from"`
+		Intensity struct {
+			Forecast *float64 `json:"forecast"`
+			Actual   *float64 `json:"actual"`
+		} `json:"intensity"`
+	} `json:"data"`
+}
+
+// CarbonIntensity returns the carbon intensity of the half hour that contains at, or the current one. It prefers
+// the actual intensity and uses the forecast when the actual one is not published yet.
+func (u *UKCarbonIntensity) CarbonIntensity(ctx context.Context, geo string, at time.Time) (Intensity, error) {
+	normalized := request.NormalizeGeo(geo)
+	if normalized != ukGeo && !strings.HasPrefix(normalized, "GB-") {
+		return Intensity{}, fmt.Errorf("%w: %q", ErrUnsupportedGeo, geo)
+	}
+
+	path := "/intensity"
+	if !at.IsZero() {
+		path += "/" + at.UTC().Truncate(30*time.Minute).Format(ukCarbonIntensityTimeLayout)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.BaseURL+path, nil)
+	if err != nil {
+		return Intensity{}, err
+	}
+
+	var resp ukCarbonIntensityResponse
+	if err := doJSON(u.HTTPClient, req, &resp); err != nil {
+		return Intensity{}, err
+	}
+	if len(resp.Data) == 0 {
+		return Intensity{}, fmt.Errorf("no carbon intensity for %s", path)
+	}
+	period := resp.Data[0]
+	gramsPerKWh := period.Intensity.Actual
+	if gramsPerKWh == nil {
+		gramsPerKWh = period.Intensity.Forecast
+	}
+	if gramsPerKWh == nil {
+		return Intensity{}, fmt.Errorf("no carbon intensity for %s", path)
+	}
+	from, err := time.Parse(ukCarbonIntensityTimeLayout, period.From)
+	if err != nil {
+		return Intensity{}, fmt.Errorf("invalid datetime: %w", err)
+	}
+	return Intensity{Geo: ukGeo, GWP: *gramsPerKWh / 1000, At: from}, nil
+}
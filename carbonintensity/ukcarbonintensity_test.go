@@ -0,0 +1,77 @@
+package carbonintensity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUKCarbonIntensity_CarbonIntensity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/intensity":
+			fmt.Fprint(w, `{"data":[{"from":"2025-01-15T10:00Z","to":"2025-01-15T10:30Z",`+
+				`"intensity":{"forecast":150,"actual":null,"index":"moderate"}}]}`)
+		case "/intensity/2025-01-14T18:30Z":
+			fmt.Fprint(w, `{"data":[{"from":"2025-01-14T18:30Z","to":"2025-01-14T19:00Z",`+
+				`"intensity":{"forecast":210,"actual":198,"index":"moderate"}}]}`)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		geo           string
+		at            time.Time
+		want          Intensity
+		expectedError string
+	}{
+		{
+			name: "should use the forecast until the actual intensity is published",
+			geo:  "GB",
+			want: Intensity{Geo: "GBR", GWP: 0.15, At: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "should return the actual intensity of the half hour",
+			geo:  "UK",
+			at:   time.Date(2025, 1, 14, 18, 45, 0, 0, time.UTC),
+			want: Intensity{Geo: "GBR", GWP: 0.198, At: time.Date(2025, 1, 14, 18, 30, 0, 0, time.UTC)},
+		},
+		{
+			name: "should use the national intensity for regions",
+			geo:  "GB-SCT",
+			want: Intensity{Geo: "GBR", GWP: 0.15, At: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:          "should report missing data",
+			geo:           "GB",
+			at:            time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedError: "no carbon intensity for /intensity/2020-01-01T00:00Z",
+		},
+		{
+			name:          "should reject geos outside of the United Kingdom",
+			geo:           "IE",
+			expectedError: `unsupported geo: "IE"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewUKCarbonIntensity()
+			source.BaseURL = server.URL
+			got, err := source.CarbonIntensity(context.Background(), tt.geo, tt.at)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package carbonintensity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/omegabytes/ecologits-go/request"
+)
+
+const (
+	// WattTimeBaseURL is the base URL of the WattTime API.
+	WattTimeBaseURL = "https://api.watttime.org"
+	// kgPerLb converts the pounds of WattTime signals to kilograms.
+	kgPerLb = 0.45359237
+)
+
+// wattTimeRegions maps the regions of the electricity mix dataset to WattTime grid regions.
+var wattTimeRegions = map[string]string{
+	"US-BPA":   "BPA",
+	"US-CAISO": "CAISO_NORTH",
+}
+
+// WattTime is a Source backed by the WattTime API. WattTime publishes marginal operating emission rates (MOER),
+// the intensity of the plants that respond to a change in demand, which differ from the average intensities of
+// the static dataset. Its intensities are marked Marginal, and estimates that use them carry a warning.
+type WattTime struct {
+	Username   string
+	Password   string
+	BaseURL    string
+	HTTPClient *http.Client
+	// Regions maps geos to WattTime regions, in addition to the built-in mapping, eg {"US-CAISO": "CAISO_NORTH"}.
+	Regions map[string]string
+
+	mu    sync.Mutex
+	token string
+}
+
+// NewWattTime returns a WattTime source that logs in with username and password.
+func NewWattTime(username, password string) *WattTime {
+	return &WattTime{Username: username, Password: password, BaseURL: WattTimeBaseURL}
+}
+
+type wattTimeLoginResponse struct {
+	Token string `json:"token"`
+}
+
+type wattTimeSignalResponse struct {
+	Data []struct {
+		PointTime time.Time `json:"point_time"`
+		Value     float64   `json:"value"`
+	} `json:"data"`
+	Meta struct {
+		Units string `json:"units"`
+	} `json:"meta"`
+}
+
+// CarbonIntensity returns the mean MOER of geo over the hour that contains at, or the current MOER.
+func (w *WattTime) CarbonIntensity(ctx context.Context, geo string, at time.Time) (Intensity, error) {
+	normalized := request.NormalizeGeo(geo)
+	region, ok := w.Regions[normalized]
+	if !ok {
+		region, ok = wattTimeRegions[normalized]
+	}
+	if !ok {
+		return Intensity{}, fmt.Errorf("%w: %q", ErrUnsupportedGeo, geo)
+	}
+
+	query := url.Values{"region": {region}, "signal_type": {"co2_moer"}}
+	endpoint := "/v3/forecast"
+	hour := at.UTC().Truncate(time.Hour)
+	if at.IsZero() {
+		query.Set("horizon_hours", "0")
+	} else {
+		endpoint = "/v3/historical"
+		query.Set("start", hour.Format(time.RFC3339))
+		query.Set("end", hour.Add(time.Hour).Format(time.RFC3339))
+	}
+
+	var resp wattTimeSignalResponse
+	if err := w.get(ctx, endpoint+"?"+query.Encode(), &resp); err != nil {
+		return Intensity{}, err
+	}
+	if len(resp.Data) == 0 {
+		return Intensity{}, fmt.Errorf("no MOER for region %q", region)
+	}
+	if resp.Meta.Units != "lbs_co2_per_mwh" {
+		return Intensity{}, fmt.Errorf("unsupported MOER units %q", resp.Meta.Units)
+	}
+
+	if at.IsZero() {
+		current := resp.Data[0]
+		return Intensity{Geo: normalized, GWP: current.Value * kgPerLb / 1000, At: current.PointTime, Marginal: true}, nil
+	}
+	var sum float64
+	for _, point := range resp.Data {
+		sum += point.Value
+	}
+	lbsPerMWh := sum / float64(len(resp.Data))
+	return Intensity{Geo: normalized, GWP: lbsPerMWh * kgPerLb / 1000, At: hour, Marginal: true}, nil
+}
+
+// get sends an authenticated request, logging in again once if the token expired.
+func (w *WattTime) get(ctx context.Context, path string, out any) error {
+	for attempt := 0; ; attempt++ {
+		token, err := w.login(ctx)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.BaseURL+path, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		err = doJSON(w.HTTPClient, req, out)
+		var statusErr *StatusError
+		if attempt == 0 && errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized {
+			w.mu.Lock()
+			w.token = ""
+			w.mu.Unlock()
+			continue
+		}
+		return err
+	}
+}
+
+// login returns the cached token, or logs in to get a new one.
+func (w *WattTime) login(ctx context.Context) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.token != "" {
+		return w.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.BaseURL+"/login", nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(w.Username, w.Password)
+	var resp wattTimeLoginResponse
+	if err := doJSON(w.HTTPClient, req, &resp); err != nil {
+		return "", fmt.Errorf("failed to log in to WattTime: %w", err)
+	}
+	if resp.Token == "" {
+		return "", errors.New("failed to log in to WattTime: empty token")
+	}
+	w.token = resp.Token
+	return w.token, nil
+}
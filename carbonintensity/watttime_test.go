@@ -0,0 +1,77 @@
+package carbonintensity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWattTime_CarbonIntensity(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			logins++
+			fmt.Fprintf(w, `{"token":"token-%d"}`, logins)
+			return
+		}
+		// The first token is treated as expired to exercise the login retry.
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("region") != "CAISO_NORTH" || query.Get("signal_type") != "co2_moer" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/v3/forecast":
+			fmt.Fprint(w, `{"data":[{"point_time":"2025-01-15T10:05:00Z","value":1000},`+
+				`{"point_time":"2025-01-15T10:10:00Z","value":900}],"meta":{"units":"lbs_co2_per_mwh"}}`)
+		case "/v3/historical":
+			if query.Get("start") != "2025-01-15T10:00:00Z" || query.Get("end") != "2025-01-15T11:00:00Z" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"data":[{"point_time":"2025-01-15T10:00:00Z","value":800},`+
+				`{"point_time":"2025-01-15T10:05:00Z","value":1200}],"meta":{"units":"lbs_co2_per_mwh"}}`)
+		}
+	}))
+	defer server.Close()
+
+	source := NewWattTime("user", "pass")
+	source.BaseURL = server.URL
+	ctx := context.Background()
+
+	got, err := source.CarbonIntensity(ctx, "US-CAISO", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "US-CAISO", got.Geo)
+	assert.InEpsilon(t, 0.45359237, got.GWP, 1e-9)
+	assert.Equal(t, time.Date(2025, 1, 15, 10, 5, 0, 0, time.UTC), got.At)
+	assert.True(t, got.Marginal, "MOER is a marginal intensity")
+	assert.Equal(t, 2, logins)
+
+	got, err = source.CarbonIntensity(ctx, "US-CAISO", time.Date(2025, 1, 15, 10, 40, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.InEpsilon(t, 0.45359237, got.GWP, 1e-9, "should average the points of the hour")
+	assert.Equal(t, time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC), got.At)
+	assert.Equal(t, 2, logins, "should reuse the token")
+
+	_, err = source.CarbonIntensity(ctx, "FR", time.Time{})
+	assert.EqualError(t, err, `unsupported geo: "FR"`)
+
+	wrong := NewWattTime("user", "wrong")
+	wrong.BaseURL = server.URL
+	_, err = wrong.CarbonIntensity(ctx, "US-CAISO", time.Time{})
+	assert.EqualError(t, err, "failed to log in to WattTime: unexpected status 403: ")
+}
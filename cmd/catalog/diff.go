@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+)
+
+// runDiff compares two catalog files and lists the models whose estimates would change.
+func runDiff(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	all := flags.Bool("all", false, "also list models whose warnings or sources changed")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New("diff requires two catalog files")
+	}
+
+	previous, err := aimodel.FetchAIModels(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("%s: %w", flags.Arg(0), err)
+	}
+	next, err := aimodel.FetchAIModels(flags.Arg(1))
+	if err != nil {
+		return fmt.Errorf("%s: %w", flags.Arg(1), err)
+	}
+
+	diff := aimodel.DiffCatalogs(previous, next)
+	fmt.Fprintf(stdout, "previous: %s\nnext:     %s\n", diff.Previous, diff.Next)
+	printDiff(stdout, diff, *all)
+	return nil
+}
+
+// printDiff writes the added, removed and changed models. Unless all is set, only changes that affect
+// impact estimates are listed.
+func printDiff(w io.Writer, diff aimodel.CatalogDiff, all bool) {
+	changed := make([]aimodel.ModelChange, 0, len(diff.Changed))
+	for _, change := range diff.Changed {
+		if all || change.AffectsEstimates() {
+			changed = append(changed, change)
+		}
+	}
+
+	fmt.Fprintf(w, "models: %d added, %d removed, %d changed\n", len(diff.Added), len(diff.Removed), len(changed))
+	printList(w, "+", diff.Added)
+	printList(w, "-", diff.Removed)
+	for _, change := range changed {
+		fmt.Fprintf(w, "  ~ %s\n", change.Model)
+		for _, field := range change.Fields {
+			fmt.Fprintf(w, "      %s: %q -> %q\n", field.Field, field.Previous, field.Next)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+)
+
+func TestRunDiff(t *testing.T) {
+	previous := writeFixture(t, "previous.json", `{
+		"version": "1.0.0",
+		"models": [
+			{"provider": "openai", "name": "gpt-4", "architecture": {"type": "dense", "parameters": 100}},
+			{
+				"provider": "openai", "name": "gpt-4o", "architecture": {"type": "dense", "parameters": 200},
+				"sources": ["https://example.com/old"]
+			},
+			{"provider": "openai", "name": "gpt-3.5-turbo", "architecture": {"type": "dense", "parameters": 20}}
+		]
+	}`)
+	next := writeFixture(t, "next.json", `{
+		"version": "1.1.0",
+		"models": [
+			{"provider": "openai", "name": "gpt-4", "architecture": {"type": "dense", "parameters": 150}},
+			{
+				"provider": "openai", "name": "gpt-4o", "architecture": {"type": "dense", "parameters": 200},
+				"sources": ["https://example.com/new"]
+			},
+			{"provider": "openai", "name": "gpt-5", "architecture": {"type": "dense", "parameters": 600}}
+		]
+	}`)
+	previousData, err := aimodel.FetchAIModels(previous)
+	require.NoError(t, err)
+	nextData, err := aimodel.FetchAIModels(next)
+	require.NoError(t, err)
+	header := "previous: " + previousData.CatalogVersion().String() + "\n" +
+		"next:     " + nextData.CatalogVersion().String() + "\n"
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:     "should list changes that affect estimates",
+			args:     []string{"diff", previous, next},
+			wantCode: 0,
+			wantStdout: header + `models: 1 added, 1 removed, 1 changed
+  + openai/gpt-5
+  - openai/gpt-3.5-turbo
+  ~ openai/gpt-4
+      parameters.total: "100" -> "150"
+      parameters.active: "100" -> "150"
+`,
+		},
+		{
+			name:     "should also list metadata changes with -all",
+			args:     []string{"diff", "-all", previous, next},
+			wantCode: 0,
+			wantStdout: header + `models: 1 added, 1 removed, 2 changed
+  + openai/gpt-5
+  - openai/gpt-3.5-turbo
+  ~ openai/gpt-4
+      parameters.total: "100" -> "150"
+      parameters.active: "100" -> "150"
+  ~ openai/gpt-4o
+      sources: "https://example.com/old" -> "https://example.com/new"
+`,
+		},
+		{
+			name:     "should report identical catalogs as unchanged",
+			args:     []string{"diff", previous, previous},
+			wantCode: 0,
+			wantStdout: "previous: " + previousData.CatalogVersion().String() + "\n" +
+				"next:     " + previousData.CatalogVersion().String() + "\n" +
+				"models: 0 added, 0 removed, 0 changed\n",
+		},
+		{
+			name:       "should require two files",
+			args:       []string{"diff", previous},
+			wantCode:   1,
+			wantStderr: "catalog: diff requires two catalog files\n",
+		},
+		{
+			name:       "should name the file that cannot be read",
+			args:       []string{"diff", previous, "missing.json"},
+			wantCode:   1,
+			wantStderr: "catalog: missing.json: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCommand(tt.args...)
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.wantStdout, stdout)
+			if tt.wantStderr == "" {
+				assert.Empty(t, stderr)
+			} else {
+				assert.True(t, strings.HasPrefix(stderr, tt.wantStderr), stderr)
+			}
+		})
+	}
+}
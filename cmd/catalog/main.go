@@ -0,0 +1,106 @@
+// Command catalog maintains the model catalog embedded in the aimodel package.
+//
+// Usage:
+//
+//	catalog validate [-overlay] file...
+//	catalog sync [-models models.json] [-mixes electricity_mixes.csv] [-version v] [-dry-run]
+//	catalog diff [-all] previous.json next.json
+//	catalog schema
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+)
+
+const usage = `usage: catalog <command> [arguments]
+
+commands:
+  validate [-overlay] file...  report problems in catalog files
+  sync [flags]                 import upstream EcoLogits models and electricity mixes
+  diff [-all] previous next    list models whose architecture, parameters or precision changed
+  schema                       print the catalog JSON Schema
+`
+
+// errProblems is returned by commands that ran successfully but found problems to report.
+var errProblems = errors.New("problems found")
+
+func main() {
+	os.Exit(exitCode(run(os.Args[1:], os.Stdout, os.Stderr), os.Stderr))
+}
+
+// exitCode reports err on stderr and returns the exit status of the command: 0 on success and 1 on failure,
+// including when problems were found and already reported on stdout.
+func exitCode(err error, stderr io.Writer) int {
+	if err == nil {
+		return 0
+	}
+	if !errors.Is(err, errProblems) {
+		fmt.Fprintln(stderr, "catalog:", err)
+	}
+	return 1
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return errors.New("missing command")
+	}
+
+	switch args[0] {
+	case "validate":
+		return runValidate(args[1:], stdout, stderr)
+	case "sync":
+		return runSync(args[1:], stdout, stderr)
+	case "diff":
+		return runDiff(args[1:], stdout, stderr)
+	case "schema":
+		_, err := stdout.Write(aimodel.CatalogSchema())
+		return err
+	default:
+		fmt.Fprint(stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func runValidate(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	overlay := flags.Bool("overlay", false, "allow aliases to point at models of the embedded catalog")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errors.New("validate requires at least one catalog file")
+	}
+
+	var base *aimodel.Registry
+	if *overlay {
+		registry, err := aimodel.DefaultRegistry()
+		if err != nil {
+			return fmt.Errorf("failed to load embedded catalog: %w", err)
+		}
+		base = registry
+	}
+
+	found := false
+	for _, source := range flags.Args() {
+		problems, err := aimodel.ValidateCatalogFile(source, base)
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(stdout, "%s: %s\n", source, problem)
+		}
+		found = found || len(problems) > 0
+	}
+	if found {
+		return errProblems
+	}
+	return nil
+}
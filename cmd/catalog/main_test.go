@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFixture writes content to name in a temporary directory and returns its path.
+func writeFixture(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// runCommand runs the command with args and returns its exit code, stdout and stderr.
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := exitCode(run(args, &stdout, &stderr), &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunValidate(t *testing.T) {
+	valid := writeFixture(t, "valid.json", `{
+		"version": "1",
+		"models": [{"provider": "openai", "name": "m", "architecture": {"type": "dense", "parameters": 7}}],
+		"aliases": [{"provider": "openai", "name": "m-latest", "alias": "m"}]
+	}`)
+	invalid := writeFixture(t, "invalid.json", `{
+		"version": "1",
+		"models": [{"provider": "nobody", "name": "m", "architecture": {"type": "dense", "parameters": 7}}]
+	}`)
+	overlay := writeFixture(t, "overlay.yaml", `
+version: "1"
+models: []
+aliases:
+  - provider: openai
+    name: prod-gpt
+    alias: gpt-4
+`)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout []string
+		wantStderr string
+	}{
+		{
+			name: "should accept valid catalogs",
+			args: []string{"validate", valid},
+		},
+		{
+			name:       "should report every problem with its file and path",
+			args:       []string{"validate", valid, invalid},
+			wantCode:   1,
+			wantStdout: []string{invalid + `: $.models[0].provider: unknown provider "nobody"`},
+		},
+		{
+			name:     "should reject aliases to embedded models without -overlay",
+			args:     []string{"validate", overlay},
+			wantCode: 1,
+			wantStdout: []string{
+				overlay + `: $.aliases[0].alias: alias "prod-gpt" does not resolve to a model`,
+			},
+		},
+		{
+			name: "should resolve aliases against the embedded catalog with -overlay",
+			args: []string{"validate", "-overlay", overlay},
+		},
+		{
+			name:       "should fail on unreadable files",
+			args:       []string{"validate", filepath.Join(t.TempDir(), "missing.json")},
+			wantCode:   1,
+			wantStderr: "catalog: ",
+		},
+		{
+			name:       "should require a file",
+			args:       []string{"validate"},
+			wantCode:   1,
+			wantStderr: "catalog: validate requires at least one catalog file\n",
+		},
+		{
+			name:       "should reject unknown commands",
+			args:       []string{"lint"},
+			wantCode:   1,
+			wantStderr: "catalog: unknown command \"lint\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCommand(tt.args...)
+			assert.Equal(t, tt.wantCode, code)
+			var lines []string
+			if stdout != "" {
+				lines = strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+			}
+			assert.Equal(t, tt.wantStdout, lines)
+			if tt.wantStderr == "" {
+				assert.Empty(t, stderr)
+			} else {
+				assert.Contains(t, stderr, tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunValidate_EmbeddedCatalog(t *testing.T) {
+	code, stdout, stderr := runCommand("validate", "../../aimodel/data/aimodels.json")
+	assert.Equal(t, 0, code)
+	assert.Empty(t, stdout)
+	assert.Empty(t, stderr)
+}
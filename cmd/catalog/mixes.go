@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/omegabytes/ecologits-go/request"
+)
+
+// syncMixes converts an upstream electricity mix CSV into the dataset format: one row per geo, sorted,
+// with columns geo, adpe (kgSbeq/kWh), gwp (kgCO2eq/kWh) and pe (MJ/kWh). Geos are normalized as by
+// request.NormalizeGeo, eg upstream's "WOR" is written as "WORLD", so that the dataset keys match lookups.
+func syncMixes(source, mixesPath string, dryRun bool, stdout io.Writer) error {
+	upstream, err := readMixes(source)
+	if err != nil {
+		return fmt.Errorf("failed to read upstream electricity mixes: %w", err)
+	}
+	current, err := readMixes(mixesPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read current electricity mixes: %w", err)
+	}
+
+	var added, removed, changed []string
+	for geo, mix := range upstream {
+		old, ok := current[geo]
+		switch {
+		case !ok:
+			added = append(added, geo)
+		case old != mix:
+			changed = append(changed, geo)
+		}
+	}
+	for geo := range current {
+		if _, ok := upstream[geo]; !ok {
+			removed = append(removed, geo)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	slices.Sort(changed)
+
+	fmt.Fprintf(stdout, "electricity mixes: %d added, %d removed, %d changed\n",
+		len(added), len(removed), len(changed))
+	printList(stdout, "+", added)
+	printList(stdout, "-", removed)
+	printList(stdout, "~", changed)
+
+	if dryRun {
+		return nil
+	}
+	encoded, err := encodeMixes(upstream)
+	if err != nil {
+		return err
+	}
+	return writeFile(mixesPath, encoded)
+}
+
+// readMixes reads a CSV with request.ParseElectricityMixes, which also accepts the upstream dataset, and keys
+// its mixes by geo.
+func readMixes(source string) (map[string]request.ElectricityMix, error) {
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	mixes, err := request.ParseElectricityMixes(f)
+	if err != nil {
+		return nil, err
+	}
+	byGeo := make(map[string]request.ElectricityMix, len(mixes))
+	for _, mix := range mixes {
+		byGeo[mix.Geo] = mix
+	}
+	return byGeo, nil
+}
+
+func encodeMixes(mixes map[string]request.ElectricityMix) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(request.ElectricityMixColumns()); err != nil {
+		return nil, err
+	}
+	for _, geo := range slices.Sorted(maps.Keys(mixes)) {
+		mix := mixes[geo]
+		row := []string{mix.Geo, formatFactor(mix.ADPe), formatFactor(mix.GWP), formatFactor(mix.PE)}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode electricity mixes: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func formatFactor(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+)
+
+const (
+	defaultCatalogPath = "aimodel/data/aimodels.json"
+	defaultMixesPath   = "request/data/electricity_mixes.csv"
+)
+
+// runSync converts upstream EcoLogits data files into the embedded catalog and electricity mix dataset,
+// and reports what changed.
+func runSync(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	modelsSource := flags.String("models", "", "path to an upstream EcoLogits models.json")
+	mixesSource := flags.String("mixes", "", "path to an upstream EcoLogits electricity_mixes.csv")
+	catalogPath := flags.String("out", defaultCatalogPath, "catalog file to regenerate")
+	mixesPath := flags.String("mixes-out", defaultMixesPath, "electricity mix dataset to regenerate")
+	version := flags.String("version", "", "version to stamp on the catalog, defaults to the current version")
+	dryRun := flags.Bool("dry-run", false, "report changes without writing files")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *modelsSource == "" && *mixesSource == "" {
+		return errors.New("sync requires -models, -mixes or both")
+	}
+
+	if *modelsSource != "" {
+		if err := syncModels(*modelsSource, *catalogPath, *version, *dryRun, stdout); err != nil {
+			return err
+		}
+	}
+	if *mixesSource != "" {
+		if err := syncMixes(*mixesSource, *mixesPath, *dryRun, stdout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func syncModels(source, catalogPath, version string, dryRun bool, stdout io.Writer) error {
+	upstream, err := aimodel.FetchAIModels(source)
+	if err != nil {
+		return fmt.Errorf("failed to read upstream models: %w", err)
+	}
+	current, err := aimodel.FetchAIModels(catalogPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read current catalog: %w", err)
+	}
+
+	imported := aimodel.ImportUpstreamCatalog(upstream, current)
+	switch {
+	case version != "":
+		imported.Version = version
+	case current != nil:
+		imported.Version = current.Version
+	}
+	encoded, err := aimodel.EncodeCatalog(imported)
+	if err != nil {
+		return err
+	}
+
+	diff := aimodel.DiffCatalogs(current, imported)
+	printDiff(stdout, diff, true)
+	if !diff.Empty() && diff.Previous.Version == diff.Next.Version {
+		fmt.Fprintf(stdout, "warning: models changed but the catalog version is still %q, set -version\n",
+			diff.Next.Version)
+	}
+
+	if dryRun {
+		return nil
+	}
+	return writeFile(catalogPath, encoded)
+}
+
+func printList(w io.Writer, prefix string, items []string) {
+	for _, item := range items {
+		fmt.Fprintf(w, "  %s %s\n", prefix, item)
+	}
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+	"github.com/omegabytes/ecologits-go/request"
+)
+
+func TestRunSync_Mixes(t *testing.T) {
+	// Upstream names the geo column "name", orders the columns differently and uses "WOR" for the world.
+	upstream := `name,adpe,pe,gwp
+WOR,7.378e-08,9.988,0.59
+FR,4.858e-08,11.289,0.07
+USA,9.855e-08,11.358,0.41
+`
+	current := `geo,adpe,gwp,pe
+DEU,6.1e-08,0.38,10.2
+FRA,4.858e-08,0.07,11.289
+USA,9.855e-08,0.4,11.358
+`
+
+	tests := []struct {
+		name       string
+		current    string
+		dryRun     bool
+		wantStdout string
+		wantFile   string
+	}{
+		{
+			name:    "should report added, removed and changed geos and regenerate the dataset",
+			current: current,
+			wantStdout: `electricity mixes: 1 added, 1 removed, 1 changed
+  + WORLD
+  - DEU
+  ~ USA
+`,
+			wantFile: `geo,adpe,gwp,pe
+FRA,4.858e-08,0.07,11.289
+USA,9.855e-08,0.41,11.358
+WORLD,7.378e-08,0.59,9.988
+`,
+		},
+		{
+			name:    "should leave the dataset unchanged on dry runs",
+			current: current,
+			dryRun:  true,
+			wantStdout: `electricity mixes: 1 added, 1 removed, 1 changed
+  + WORLD
+  - DEU
+  ~ USA
+`,
+			wantFile: current,
+		},
+		{
+			name: "should create a missing dataset",
+			wantStdout: `electricity mixes: 3 added, 0 removed, 0 changed
+  + FRA
+  + USA
+  + WORLD
+`,
+			wantFile: `geo,adpe,gwp,pe
+FRA,4.858e-08,0.07,11.289
+USA,9.855e-08,0.41,11.358
+WORLD,7.378e-08,0.59,9.988
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := writeFixture(t, "upstream.csv", upstream)
+			out := filepath.Join(t.TempDir(), "electricity_mixes.csv")
+			if tt.current != "" {
+				require.NoError(t, os.WriteFile(out, []byte(tt.current), 0o600))
+			}
+			args := []string{"sync", "-mixes", source, "-mixes-out", out}
+			if tt.dryRun {
+				args = append(args, "-dry-run")
+			}
+
+			code, stdout, stderr := runCommand(args...)
+			assert.Equal(t, 0, code, stderr)
+			assert.Equal(t, tt.wantStdout, stdout)
+			written, err := os.ReadFile(out)
+			if tt.wantFile == "" {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFile, string(written))
+		})
+	}
+}
+
+func TestRunSync_MixesReadByRequest(t *testing.T) {
+	source := writeFixture(t, "upstream.csv", "name,adpe,pe,gwp\nWOR,7.378e-08,9.988,0.59\nFR,4.858e-08,11.289,0.07\n")
+	out := filepath.Join(t.TempDir(), "electricity_mixes.csv")
+	code, _, stderr := runCommand("sync", "-mixes", source, "-mixes-out", out)
+	require.Equal(t, 0, code, stderr)
+
+	written, err := os.ReadFile(out)
+	require.NoError(t, err)
+	mixes, err := request.ParseElectricityMixes(bytes.NewReader(written))
+	require.NoError(t, err)
+	database := request.NewMixDatabase(mixes)
+	for _, geo := range []string{"", "WOR", "FR", "FRA"} {
+		_, err := database.Lookup(geo)
+		assert.NoError(t, err, geo)
+	}
+}
+
+func TestRunSync_Models(t *testing.T) {
+	upstream := `{
+		"aliases": [{"type": "alias", "provider": "openai", "name": "gpt-4o-2024", "alias": "gpt-4o"}],
+		"models": [
+			{
+				"type": "model", "provider": "openai", "name": "gpt-4o",
+				"architecture": {"type": "dense", "parameters": {"min": 200, "max": 400}},
+				"warnings": ["model-arch-not-released"], "sources": ["https://example.com"]
+			},
+			{
+				"type": "model", "provider": "openai", "name": "gpt-5",
+				"architecture": {"type": "dense", "parameters": 600}
+			}
+		]
+	}`
+	current := `{
+		"version": "1.0.0",
+		"aliases": [{"provider": "openai", "name": "gpt-4o-2024", "alias": "gpt-4o", "precision": "fp8"}],
+		"models": [
+			{
+				"provider": "openai", "name": "gpt-4o", "precision": "fp8",
+				"architecture": {"type": "dense", "parameters": {"min": 100, "max": 200}}
+			},
+			{"provider": "openai", "name": "gpt-3.5-turbo", "architecture": {"type": "dense", "parameters": 20}},
+			{
+				"provider": "openai", "name": "text-embedding-3-small", "task": "embeddings",
+				"architecture": {"type": "dense", "parameters": 1}
+			}
+		]
+	}`
+
+	tests := []struct {
+		name        string
+		version     string
+		wantStdout  string
+		wantVersion string
+	}{
+		{
+			name: "should report model changes and warn when the version is unchanged",
+			wantStdout: `models: 1 added, 1 removed, 1 changed
+  + openai/gpt-5
+  - openai/gpt-3.5-turbo
+  ~ openai/gpt-4o
+      parameters.total: "100-200" -> "200-400"
+      parameters.active: "100-200" -> "200-400"
+      warnings: "" -> "model-arch-not-released"
+      sources: "" -> "https://example.com"
+warning: models changed but the catalog version is still "1.0.0", set -version
+`,
+			wantVersion: "1.0.0",
+		},
+		{
+			name:    "should stamp the given version",
+			version: "1.1.0",
+			wantStdout: `models: 1 added, 1 removed, 1 changed
+  + openai/gpt-5
+  - openai/gpt-3.5-turbo
+  ~ openai/gpt-4o
+      parameters.total: "100-200" -> "200-400"
+      parameters.active: "100-200" -> "200-400"
+      warnings: "" -> "model-arch-not-released"
+      sources: "" -> "https://example.com"
+`,
+			wantVersion: "1.1.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := writeFixture(t, "models.json", upstream)
+			out := writeFixture(t, "aimodels.json", current)
+			args := []string{"sync", "-models", source, "-out", out}
+			if tt.version != "" {
+				args = append(args, "-version", tt.version)
+			}
+
+			code, stdout, stderr := runCommand(args...)
+			assert.Equal(t, 0, code, stderr)
+			assert.Equal(t, tt.wantStdout, stdout)
+
+			written, err := aimodel.FetchAIModels(out)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVersion, written.Version)
+			r := aimodel.NewRegistry(written)
+			model, err := r.Lookup(aimodel.OpenAI, "gpt-4o")
+			require.NoError(t, err)
+			assert.Equal(t, aimodel.FP8, model.Precision(), "precision is not tracked upstream and must be kept")
+			_, err = r.Lookup(aimodel.OpenAI, "text-embedding-3-small")
+			assert.NoError(t, err, "non-chat models are not tracked upstream and must be kept")
+			_, err = r.Lookup(aimodel.OpenAI, "gpt-3.5-turbo")
+			assert.ErrorIs(t, err, aimodel.ErrModelNotFound)
+		})
+	}
+}
+
+func TestRunSync_Errors(t *testing.T) {
+	source := writeFixture(t, "upstream.csv", "name,adpe,gwp\nFR,4.858e-08,0.07\n")
+	tests := []struct {
+		name       string
+		args       []string
+		wantStderr string
+	}{
+		{
+			name:       "should require a source",
+			args:       []string{"sync"},
+			wantStderr: "catalog: sync requires -models, -mixes or both\n",
+		},
+		{
+			name:       "should reject upstream mixes with missing columns",
+			args:       []string{"sync", "-mixes", source, "-mixes-out", filepath.Join(t.TempDir(), "out.csv")},
+			wantStderr: `catalog: failed to read upstream electricity mixes: column "pe" is missing` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := runCommand(tt.args...)
+			assert.Equal(t, 1, code)
+			assert.Empty(t, strings.TrimSpace(stdout))
+			assert.Equal(t, tt.wantStderr, stderr)
+		})
+	}
+}
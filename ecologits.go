@@ -1,6 +1,9 @@
 package ecologits_go
 
 import (
+	"context"
+	"time"
+
 	"github.com/omegabytes/ecologits-go/aimodel"
 	"github.com/omegabytes/ecologits-go/gpuserver"
 	"github.com/omegabytes/ecologits-go/impact"
@@ -20,8 +23,89 @@ func NewGPUServer() (*gpuserver.GPUServer, error) {
 	return gpuserver.GenericGPUServer()
 }
 
-func NewRequest(outputTokenCount int64, latency float64, geo string) (request.Request, error) {
-	return request.Request{OutputTokenCount: float64(outputTokenCount), Latency: latency, Geo: geo}, nil
+// NewRequest builds and validates a chat request. Additional fields, eg input tokens or a timestamp, can be set
+// with request options.
+func NewRequest(
+	outputTokenCount int64,
+	latency time.Duration,
+	geo string,
+	opts ...request.Option,
+) (request.Request, error) {
+	base := []request.Option{
+		request.WithOutputTokens(outputTokenCount),
+		request.WithLatency(latency),
+		request.WithGeo(geo),
+	}
+	return request.New(append(base, opts...)...)
+}
+
+// NewEmbeddingRequest builds and validates an embedding request.
+func NewEmbeddingRequest(
+	inputTokenCount int64,
+	batchSize int,
+	latency time.Duration,
+	geo string,
+) (request.EmbeddingRequest, error) {
+	req := request.EmbeddingRequest{
+		InputTokenCount: float64(inputTokenCount),
+		BatchSize:       batchSize,
+		Latency:         latency,
+		Location:        request.Location{Geo: geo},
+	}
+	if err := req.Validate(); err != nil {
+		return request.EmbeddingRequest{}, err
+	}
+	return req, nil
+}
+
+// NewImageRequest builds and validates an image generation request. Zero steps means request.DefaultImageSteps.
+func NewImageRequest(
+	imageCount, width, height, steps int,
+	latency time.Duration,
+	geo string,
+) (request.ImageRequest, error) {
+	req := request.ImageRequest{
+		ImageCount: imageCount,
+		Width:      width,
+		Height:     height,
+		Steps:      steps,
+		Latency:    latency,
+		Location:   request.Location{Geo: geo},
+	}
+	if err := req.Validate(); err != nil {
+		return request.ImageRequest{}, err
+	}
+	return req, nil
+}
+
+// NewTranscriptionRequest builds and validates a speech-to-text request.
+func NewTranscriptionRequest(
+	audioDuration time.Duration,
+	latency time.Duration,
+	geo string,
+) (request.TranscriptionRequest, error) {
+	req := request.TranscriptionRequest{
+		AudioDuration: audioDuration,
+		Latency:       latency,
+		Location:      request.Location{Geo: geo},
+	}
+	if err := req.Validate(); err != nil {
+		return request.TranscriptionRequest{}, err
+	}
+	return req, nil
+}
+
+// NewSpeechRequest builds and validates a text-to-speech request.
+func NewSpeechRequest(characterCount int64, latency time.Duration, geo string) (request.SpeechRequest, error) {
+	req := request.SpeechRequest{
+		CharacterCount: float64(characterCount),
+		Latency:        latency,
+		Location:       request.Location{Geo: geo},
+	}
+	if err := req.Validate(); err != nil {
+		return request.SpeechRequest{}, err
+	}
+	return req, nil
 }
 
 func ComputeImpacts(
@@ -31,3 +115,85 @@ func ComputeImpacts(
 ) (impact.Impacts, error) {
 	return impact.ComputeImpacts(aiModel, server, request)
 }
+
+func ComputeImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	request request.Request,
+	server *gpuserver.GPUServer,
+) (impact.Impacts, error) {
+	return impact.ComputeImpactsWithMixProvider(ctx, mixes, aiModel, server, request)
+}
+
+func ComputeEmbeddingImpacts(
+	aiModel *aimodel.AIModel,
+	request request.EmbeddingRequest,
+	server *gpuserver.GPUServer,
+) (impact.Impacts, error) {
+	return impact.ComputeEmbeddingImpacts(aiModel, server, request)
+}
+
+func ComputeEmbeddingImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	request request.EmbeddingRequest,
+	server *gpuserver.GPUServer,
+) (impact.Impacts, error) {
+	return impact.ComputeEmbeddingImpactsWithMixProvider(ctx, mixes, aiModel, server, request)
+}
+
+func ComputeImageImpacts(
+	aiModel *aimodel.AIModel,
+	request request.ImageRequest,
+	server *gpuserver.GPUServer,
+) (impact.Impacts, error) {
+	return impact.ComputeImageImpacts(aiModel, server, request)
+}
+
+func ComputeImageImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	request request.ImageRequest,
+	server *gpuserver.GPUServer,
+) (impact.Impacts, error) {
+	return impact.ComputeImageImpactsWithMixProvider(ctx, mixes, aiModel, server, request)
+}
+
+func ComputeTranscriptionImpacts(
+	aiModel *aimodel.AIModel,
+	request request.TranscriptionRequest,
+	server *gpuserver.GPUServer,
+) (impact.Impacts, error) {
+	return impact.ComputeTranscriptionImpacts(aiModel, server, request)
+}
+
+func ComputeTranscriptionImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	request request.TranscriptionRequest,
+	server *gpuserver.GPUServer,
+) (impact.Impacts, error) {
+	return impact.ComputeTranscriptionImpactsWithMixProvider(ctx, mixes, aiModel, server, request)
+}
+
+func ComputeSpeechImpacts(
+	aiModel *aimodel.AIModel,
+	request request.SpeechRequest,
+	server *gpuserver.GPUServer,
+) (impact.Impacts, error) {
+	return impact.ComputeSpeechImpacts(aiModel, server, request)
+}
+
+func ComputeSpeechImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	request request.SpeechRequest,
+	server *gpuserver.GPUServer,
+) (impact.Impacts, error) {
+	return impact.ComputeSpeechImpactsWithMixProvider(ctx, mixes, aiModel, server, request)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	ecogo "github.com/omegabytes/ecologits-go"
+	"github.com/omegabytes/ecologits-go/request"
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/responses"
 )
@@ -30,9 +31,12 @@ func main() {
 		slog.Error("client request failed", "error", err)
 		os.Exit(1)
 	}
-	reqLatencyMs := time.Since(start).Milliseconds()
+	reqLatency := time.Since(start)
 
-	req, err := ecogo.NewRequest(resp.Usage.OutputTokens, float64(reqLatencyMs), "USA")
+	req, err := ecogo.NewRequest(resp.Usage.OutputTokens, reqLatency, "USA",
+		request.WithInputTokens(resp.Usage.InputTokens),
+		request.WithCachedInputTokens(resp.Usage.InputTokensDetails.CachedTokens),
+	)
 	if err != nil {
 		slog.Error("failed to create new request model", "error", err)
 		return
@@ -62,6 +66,6 @@ func main() {
 		slog.Error("failed to marshal json", "error", err)
 		return
 	}
-	slog.Info("impacts", "req latency", reqLatencyMs)
+	slog.Info("impacts", "req latency", reqLatency)
 	fmt.Println("impacts: ", string(jsonData))
 }
@@ -0,0 +1,75 @@
+package gpuserver
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/omegabytes/ecologits-go/common"
+)
+
+// EmbeddingEnergyKWH returns the 95% confidence interval of the energy consumption of a single GPU in kWh to
+// embed inputTokenCount tokens split across batchSize inputs.
+func (g *GPUServer) EmbeddingEnergyKWH(
+	modelActiveParamCount float64,
+	inputTokenCount float64,
+	batchSize int,
+) (common.RangeValue, error) {
+	if err := validateEmbeddingArgs(modelActiveParamCount, inputTokenCount, batchSize); err != nil {
+		return common.RangeValue{}, err
+	}
+	gpu := g.GPUModel
+	if gpu.EmbeddingEnergyAlpha <= 0 || gpu.EmbeddingEnergyBeta <= 0 || gpu.EmbeddingEnergyStdev <= 0 {
+		return common.RangeValue{}, fmt.Errorf("GPU embedding energy parameters must be greater than 0")
+	}
+	gpuEnergyMean := inputTokenCount*gpu.EmbeddingEnergyAlpha*modelActiveParamCount +
+		float64(batchSize)*gpu.EmbeddingEnergyBeta
+	gpuEnergyInterval := inputTokenCount * 1.96 * gpu.EmbeddingEnergyStdev
+	return common.RangeValue{
+		Min: math.Max(0, gpuEnergyMean-gpuEnergyInterval),
+		Max: gpuEnergyMean + gpuEnergyInterval,
+	}, nil
+}
+
+// EmbeddingLatency returns the time in seconds a GPU is busy embedding inputTokenCount tokens split across
+// batchSize inputs, clamped to the observed request latency like GenerationLatency.
+func (g *GPUServer) EmbeddingLatency(
+	modelActiveParamCount float64,
+	inputTokenCount float64,
+	batchSize int,
+	requestLatencySecs float64,
+) (common.RangeValue, error) {
+	if err := validateEmbeddingArgs(modelActiveParamCount, inputTokenCount, batchSize); err != nil {
+		return common.RangeValue{}, err
+	}
+	if requestLatencySecs <= 0 {
+		return common.RangeValue{}, fmt.Errorf("requestLatencySecs must be greater than 0")
+	}
+	gpu := g.GPUModel
+	if gpu.EmbeddingLatencyAlpha <= 0 || gpu.EmbeddingLatencyBeta <= 0 || gpu.EmbeddingLatencyStdev <= 0 {
+		return common.RangeValue{}, fmt.Errorf("GPU embedding latency parameters must be greater than 0")
+	}
+	gpuLatencyMean := inputTokenCount*gpu.EmbeddingLatencyAlpha*modelActiveParamCount +
+		float64(batchSize)*gpu.EmbeddingLatencyBeta
+	gpuLatencyInterval := inputTokenCount * 1.96 * gpu.EmbeddingLatencyStdev
+	embeddingLatency := common.RangeValue{
+		Min: math.Max(0, gpuLatencyMean-gpuLatencyInterval),
+		Max: gpuLatencyMean + gpuLatencyInterval,
+	}
+	return clampLatency(embeddingLatency, requestLatencySecs), nil
+}
+
+func validateEmbeddingArgs(modelActiveParamCount float64, inputTokenCount float64, batchSize int) error {
+	if modelActiveParamCount <= 0 {
+		return fmt.Errorf("modelActiveParamCount must be greater than 0")
+	}
+	if inputTokenCount <= 0 {
+		return fmt.Errorf("inputTokenCount must be greater than 0")
+	}
+	if batchSize <= 0 {
+		return fmt.Errorf("batchSize must be greater than 0")
+	}
+	if float64(batchSize) > inputTokenCount {
+		return fmt.Errorf("batchSize cannot exceed inputTokenCount")
+	}
+	return nil
+}
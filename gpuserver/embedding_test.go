@@ -0,0 +1,145 @@
+package gpuserver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func embeddingGPU() GPU {
+	return GPU{
+		EmbeddingEnergyAlpha:  8.91e-10,
+		EmbeddingEnergyBeta:   1.43e-8,
+		EmbeddingEnergyStdev:  5.19e-10,
+		EmbeddingLatencyAlpha: 8.02e-6,
+		EmbeddingLatencyBeta:  2.23e-3,
+		EmbeddingLatencyStdev: 7.00e-8,
+	}
+}
+
+func TestServerInfra_EmbeddingEnergyKWH(t *testing.T) {
+	type args struct {
+		modelActiveParamCount float64
+		inputTokenCount       float64
+		batchSize             int
+	}
+	tests := []struct {
+		name          string
+		gpu           GPU
+		args          args
+		want          common.RangeValue
+		expectedError error
+	}{
+		{
+			// gpuEnergyMean: 1000 * 8.91e-10 * 1 + 10 * 1.43e-8 = 1.034e-6
+			// gpuEnergyInterval: 1000 * 1.96 * 5.19e-10 = 1.01724e-6
+			name: "should calculate embedding energy successfully",
+			gpu:  embeddingGPU(),
+			args: args{modelActiveParamCount: 1, inputTokenCount: 1000, batchSize: 10},
+			want: common.RangeValue{Min: 1.676e-8, Max: 2.05124e-6},
+		},
+		{
+			// gpuEnergyMean: 1000 * 8.91e-10 * 1 + 100 * 1.43e-8 = 2.321e-6
+			name: "should grow with the batch size for the same tokens",
+			gpu:  embeddingGPU(),
+			args: args{modelActiveParamCount: 1, inputTokenCount: 1000, batchSize: 100},
+			want: common.RangeValue{Min: 1.30376e-6, Max: 3.33824e-6},
+		},
+		{
+			name:          "should return error when inputTokenCount is 0",
+			gpu:           embeddingGPU(),
+			args:          args{modelActiveParamCount: 1, inputTokenCount: 0, batchSize: 1},
+			expectedError: fmt.Errorf("inputTokenCount must be greater than 0"),
+		},
+		{
+			name:          "should return error when batchSize is 0",
+			gpu:           embeddingGPU(),
+			args:          args{modelActiveParamCount: 1, inputTokenCount: 10, batchSize: 0},
+			expectedError: fmt.Errorf("batchSize must be greater than 0"),
+		},
+		{
+			name:          "should return error when batchSize exceeds inputTokenCount",
+			gpu:           embeddingGPU(),
+			args:          args{modelActiveParamCount: 1, inputTokenCount: 10, batchSize: 11},
+			expectedError: fmt.Errorf("batchSize cannot exceed inputTokenCount"),
+		},
+		{
+			name:          "should return error when GPU embedding energy parameters are invalid",
+			gpu:           GPU{},
+			args:          args{modelActiveParamCount: 1, inputTokenCount: 10, batchSize: 1},
+			expectedError: fmt.Errorf("GPU embedding energy parameters must be greater than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GPUServer{GPUModel: tt.gpu}
+			got, err := s.EmbeddingEnergyKWH(tt.args.modelActiveParamCount, tt.args.inputTokenCount, tt.args.batchSize)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.Min, got.Min, 1e-15)
+			assert.InDelta(t, tt.want.Max, got.Max, 1e-15)
+		})
+	}
+}
+
+func TestServerInfra_EmbeddingLatency(t *testing.T) {
+	type args struct {
+		modelActiveParamCount float64
+		inputTokenCount       float64
+		batchSize             int
+		requestLatencySecs    float64
+	}
+	tests := []struct {
+		name          string
+		gpu           GPU
+		args          args
+		want          common.RangeValue
+		expectedError error
+	}{
+		{
+			// gpuLatencyMean: 1000 * 8.02e-6 * 1 + 10 * 2.23e-3 = 0.03032
+			// gpuLatencyInterval: 1000 * 1.96 * 7.00e-8 = 1.372e-4
+			name: "should calculate embedding latency successfully",
+			gpu:  embeddingGPU(),
+			args: args{modelActiveParamCount: 1, inputTokenCount: 1000, batchSize: 10, requestLatencySecs: 1},
+			want: common.RangeValue{Min: 0.0301828, Max: 0.0304572},
+		},
+		{
+			name: "should return requestLatencySecs when it is shorter than the estimate",
+			gpu:  embeddingGPU(),
+			args: args{modelActiveParamCount: 1, inputTokenCount: 1000, batchSize: 10, requestLatencySecs: 0.02},
+			want: common.RangeValue{Min: 0.02, Max: 0.02},
+		},
+		{
+			name:          "should return error when requestLatencySecs is 0",
+			gpu:           embeddingGPU(),
+			args:          args{modelActiveParamCount: 1, inputTokenCount: 1000, batchSize: 10},
+			expectedError: fmt.Errorf("requestLatencySecs must be greater than 0"),
+		},
+		{
+			name:          "should return error when GPU embedding latency parameters are invalid",
+			gpu:           GPU{},
+			args:          args{modelActiveParamCount: 1, inputTokenCount: 1000, batchSize: 10, requestLatencySecs: 1},
+			expectedError: fmt.Errorf("GPU embedding latency parameters must be greater than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GPUServer{GPUModel: tt.gpu}
+			got, err := s.EmbeddingLatency(tt.args.modelActiveParamCount, tt.args.inputTokenCount, tt.args.batchSize,
+				tt.args.requestLatencySecs)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.Min, got.Min, 1e-12)
+			assert.InDelta(t, tt.want.Max, got.Max, 1e-12)
+		})
+	}
+}
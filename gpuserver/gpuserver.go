@@ -11,27 +11,68 @@ import (
 // Some climate impact is attributed to training or serving requests and some is attributed to server
 // construction and operation. The latter is called embodied impact.
 type GPUServer struct {
-	AvailableGPUCount  int
+	AvailableGPUCount int
+	// PowerConsumptionKW is the power draw of the server without its GPUs, in kW.
 	PowerConsumptionKW float64
+	// Embodied impacts are in kgSbeq (ADPe), kgCO2eq (GWP) and MJ (PE).
 	EmbodiedImpactADPe float64
 	EmbodiedImpactGWP  float64
 	EmbodiedImpactPE   float64
-	HardwareLifespan   int64
-	GPUModel           GPU
-	DatacenterPUE      float64
+	// HardwareLifespan is in seconds.
+	HardwareLifespan int64
+	GPUModel         GPU
+	DatacenterPUE    float64
 }
 
 // GPU represents a GPU contained in a server that is used train LLMs or execute user requestg.
 // Some climate impact is attributed to training or serving requests and some is attributed to GPU
 // manufacturing, operation, and disposal. The latter is called embodied impact.
 type GPU struct {
-	EnergyAlpha        float64
-	EnergyBeta         float64
-	EnergyStdev        float64
-	LatencyAlpha       float64
-	LatencyBeta        float64
-	LatencyStdev       float64
-	AvailMemoryGB      float64
+	// Energy and latency coefficients model token generation: energy in kWh and latency in seconds per output
+	// token, per billion active parameters (alpha) plus a constant term (beta).
+	EnergyAlpha  float64
+	EnergyBeta   float64
+	EnergyStdev  float64
+	LatencyAlpha float64
+	LatencyBeta  float64
+	LatencyStdev float64
+	// Prefill coefficients model the processing of the prompt before the first output token: energy in kWh
+	// and latency in seconds per uncached input token, per billion active parameters (alpha) plus a constant
+	// term (beta). The prefill, embedding, image and speech coefficients of GenericGPU are placeholders that
+	// have not been fitted to measurements.
+	PrefillEnergyAlpha  float64
+	PrefillEnergyBeta   float64
+	PrefillEnergyStdev  float64
+	PrefillLatencyAlpha float64
+	PrefillLatencyBeta  float64
+	PrefillLatencyStdev float64
+	// Embedding coefficients model a single forward pass over the input: energy in kWh and latency in
+	// seconds per input token and billion active parameters (alpha), and per embedded input (beta).
+	EmbeddingEnergyAlpha  float64
+	EmbeddingEnergyBeta   float64
+	EmbeddingEnergyStdev  float64
+	EmbeddingLatencyAlpha float64
+	EmbeddingLatencyBeta  float64
+	EmbeddingLatencyStdev float64
+	// Image generation coefficients model diffusion: latency in seconds per denoising step, megapixel and
+	// billion active parameters (alpha), and per image for encoding and decoding (beta).
+	ImageLatencyAlpha float64
+	ImageLatencyBeta  float64
+	ImageLatencyStdev float64
+	// Speech coefficients model GPU time as a real-time factor: seconds of GPU time per second of transcribed
+	// audio (ASR) and per synthesized character (TTS), per billion active parameters (alpha) plus a constant
+	// term (beta).
+	TranscriptionLatencyAlpha float64
+	TranscriptionLatencyBeta  float64
+	TranscriptionLatencyStdev float64
+	SpeechLatencyAlpha        float64
+	SpeechLatencyBeta         float64
+	SpeechLatencyStdev        float64
+	// PowerKW is the average power draw of the GPU in kW while it is busy, used for workloads whose energy is
+	// derived from GPU time.
+	PowerKW       float64
+	AvailMemoryGB float64
+	// Embodied impacts are in kgSbeq (ADPe), kgCO2eq (GWP) and MJ (PE).
 	EmbodiedImpactADPe float64
 	EmbodiedImpactGWP  float64
 	EmbodiedImpactPE   float64
@@ -64,12 +105,45 @@ func GenericGPUServer() (*GPUServer, error) {
 // GenericGPU returns a GPU with default values for energy and latency parameterg.
 func GenericGPU() GPU {
 	const (
-		gpuEnergyAlpha        = 8.91e-8
-		gpuEnergyBeta         = 1.43e-6
-		gpuEnergyStdev        = 5.19e-7
-		gpuLatencyAlpha       = 8.02e-4
-		gpuLatencyBeta        = 2.23e-2
-		gpuLatencyStdev       = 7.00e-6
+		gpuEnergyAlpha  = 8.91e-8
+		gpuEnergyBeta   = 1.43e-6
+		gpuEnergyStdev  = 5.19e-7
+		gpuLatencyAlpha = 8.02e-4
+		gpuLatencyBeta  = 2.23e-2
+		gpuLatencyStdev = 7.00e-6
+		// Placeholder: a 70B model prefills about 6,000 tokens per second at a draw of about 0.45 kW. Not
+		// fitted to measurements.
+		gpuPrefillEnergyAlpha  = 2.00e-10
+		gpuPrefillEnergyBeta   = 5.00e-9
+		gpuPrefillEnergyStdev  = 1.00e-10
+		gpuPrefillLatencyAlpha = 2.00e-6
+		gpuPrefillLatencyBeta  = 2.00e-5
+		gpuPrefillLatencyStdev = 1.00e-7
+		// Placeholder: the generation coefficients scaled down by two orders of magnitude, since prompt
+		// processing is compute bound and batched, except the per-batch latency beta, which is only scaled down
+		// tenfold. Not fitted to measurements.
+		gpuEmbeddingEnergyAlpha  = 8.91e-10
+		gpuEmbeddingEnergyBeta   = 1.43e-8
+		gpuEmbeddingEnergyStdev  = 5.19e-10
+		gpuEmbeddingLatencyAlpha = 8.02e-6
+		gpuEmbeddingLatencyBeta  = 2.23e-3
+		gpuEmbeddingLatencyStdev = 7.00e-8
+		// Placeholder: a 1024x1024 image with 30 steps of a 2.6B parameter diffusion model takes about 3
+		// seconds. Not fitted to measurements.
+		gpuImageLatencyAlpha = 3.30e-2
+		gpuImageLatencyBeta  = 3.00e-1
+		gpuImageLatencyStdev = 2.00e-3
+		// Placeholder: Whisper large (1.55B) transcribes an hour of audio in about a minute on a data center
+		// GPU. Not fitted to measurements.
+		gpuTranscriptionLatencyAlpha = 8.00e-3
+		gpuTranscriptionLatencyBeta  = 4.00e-3
+		gpuTranscriptionLatencyStdev = 1.00e-3
+		// Placeholder: a 1B text-to-speech model speaks about 15 characters per second at a real-time factor
+		// of 0.1. Not fitted to measurements.
+		gpuSpeechLatencyAlpha = 5.00e-3
+		gpuSpeechLatencyBeta  = 2.00e-3
+		gpuSpeechLatencyStdev = 1.00e-3
+		gpuPowerKW            = 0.4
 		gpuMemoryGB           = 80
 		gpuEmbodiedImpactGWP  = 143
 		gpuEmbodiedImpactADPe = 5.1e-3
@@ -77,16 +151,38 @@ func GenericGPU() GPU {
 	)
 
 	return GPU{
-		EnergyAlpha:        gpuEnergyAlpha,
-		EnergyBeta:         gpuEnergyBeta,
-		EnergyStdev:        gpuEnergyStdev,
-		LatencyAlpha:       gpuLatencyAlpha,
-		LatencyBeta:        gpuLatencyBeta,
-		LatencyStdev:       gpuLatencyStdev,
-		AvailMemoryGB:      gpuMemoryGB,
-		EmbodiedImpactADPe: gpuEmbodiedImpactADPe,
-		EmbodiedImpactGWP:  gpuEmbodiedImpactGWP,
-		EmbodiedImpactPE:   gpuEmbodiedImpactPE,
+		EnergyAlpha:               gpuEnergyAlpha,
+		EnergyBeta:                gpuEnergyBeta,
+		EnergyStdev:               gpuEnergyStdev,
+		LatencyAlpha:              gpuLatencyAlpha,
+		LatencyBeta:               gpuLatencyBeta,
+		LatencyStdev:              gpuLatencyStdev,
+		PrefillEnergyAlpha:        gpuPrefillEnergyAlpha,
+		PrefillEnergyBeta:         gpuPrefillEnergyBeta,
+		PrefillEnergyStdev:        gpuPrefillEnergyStdev,
+		PrefillLatencyAlpha:       gpuPrefillLatencyAlpha,
+		PrefillLatencyBeta:        gpuPrefillLatencyBeta,
+		PrefillLatencyStdev:       gpuPrefillLatencyStdev,
+		EmbeddingEnergyAlpha:      gpuEmbeddingEnergyAlpha,
+		EmbeddingEnergyBeta:       gpuEmbeddingEnergyBeta,
+		EmbeddingEnergyStdev:      gpuEmbeddingEnergyStdev,
+		EmbeddingLatencyAlpha:     gpuEmbeddingLatencyAlpha,
+		EmbeddingLatencyBeta:      gpuEmbeddingLatencyBeta,
+		EmbeddingLatencyStdev:     gpuEmbeddingLatencyStdev,
+		ImageLatencyAlpha:         gpuImageLatencyAlpha,
+		ImageLatencyBeta:          gpuImageLatencyBeta,
+		ImageLatencyStdev:         gpuImageLatencyStdev,
+		TranscriptionLatencyAlpha: gpuTranscriptionLatencyAlpha,
+		TranscriptionLatencyBeta:  gpuTranscriptionLatencyBeta,
+		TranscriptionLatencyStdev: gpuTranscriptionLatencyStdev,
+		SpeechLatencyAlpha:        gpuSpeechLatencyAlpha,
+		SpeechLatencyBeta:         gpuSpeechLatencyBeta,
+		SpeechLatencyStdev:        gpuSpeechLatencyStdev,
+		PowerKW:                   gpuPowerKW,
+		AvailMemoryGB:             gpuMemoryGB,
+		EmbodiedImpactADPe:        gpuEmbodiedImpactADPe,
+		EmbodiedImpactGWP:         gpuEmbodiedImpactGWP,
+		EmbodiedImpactPE:          gpuEmbodiedImpactPE,
 	}
 }
 
@@ -162,20 +258,30 @@ func (g *GPUServer) GenerationLatency(
 	if g.PowerConsumptionKW <= 0 {
 		return common.RangeValue{}, fmt.Errorf("PowerConsumptionKW must be greater than 0")
 	}
+	return clampLatency(g.decodeLatency(modelActiveParamCount, outputTokenCount), requestLatencySecs), nil
+}
+
+// decodeLatency returns the 95% confidence interval of the time in seconds to generate outputTokenCount tokens.
+func (g *GPUServer) decodeLatency(modelActiveParamCount float64, outputTokenCount float64) common.RangeValue {
 	gpuLatencyPerTokenMean := g.GPUModel.LatencyAlpha*modelActiveParamCount + g.GPUModel.LatencyBeta
 	gpuLatencyMin := outputTokenCount * (gpuLatencyPerTokenMean - 1.96*g.GPUModel.LatencyStdev)
 	gpuLatencyMax := outputTokenCount * (gpuLatencyPerTokenMean + 1.96*g.GPUModel.LatencyStdev)
-	gpuLatencyInterval := common.RangeValue{
+	return common.RangeValue{
 		Min: math.Max(0, gpuLatencyMin),
 		Max: gpuLatencyMax,
 	}
+}
+
+// clampLatency bounds an estimated GPU latency interval by the observed request latency: a GPU cannot be busy
+// with a request for longer than the request took.
+func clampLatency(gpuLatencyInterval common.RangeValue, requestLatencySecs float64) common.RangeValue {
 	if gpuLatencyInterval.Max < requestLatencySecs {
-		return gpuLatencyInterval, nil
+		return gpuLatencyInterval
 	}
 	return common.RangeValue{
 		Min: requestLatencySecs,
 		Max: requestLatencySecs,
-	}, nil
+	}
 }
 
 // RequestEnergy returns the energy consumption of the request in kWh.
@@ -10,16 +10,38 @@ import (
 
 func TestGenericGPU(t *testing.T) {
 	want := GPU{
-		EnergyAlpha:        8.91e-8,
-		EnergyBeta:         1.43e-6,
-		EnergyStdev:        5.19e-7,
-		LatencyAlpha:       8.02e-4,
-		LatencyBeta:        2.23e-2,
-		LatencyStdev:       7.00e-6,
-		AvailMemoryGB:      80,
-		EmbodiedImpactADPe: 5.1e-3,
-		EmbodiedImpactGWP:  143,
-		EmbodiedImpactPE:   1828,
+		EnergyAlpha:               8.91e-8,
+		EnergyBeta:                1.43e-6,
+		EnergyStdev:               5.19e-7,
+		LatencyAlpha:              8.02e-4,
+		LatencyBeta:               2.23e-2,
+		LatencyStdev:              7.00e-6,
+		PrefillEnergyAlpha:        2.00e-10,
+		PrefillEnergyBeta:         5.00e-9,
+		PrefillEnergyStdev:        1.00e-10,
+		PrefillLatencyAlpha:       2.00e-6,
+		PrefillLatencyBeta:        2.00e-5,
+		PrefillLatencyStdev:       1.00e-7,
+		EmbeddingEnergyAlpha:      8.91e-10,
+		EmbeddingEnergyBeta:       1.43e-8,
+		EmbeddingEnergyStdev:      5.19e-10,
+		EmbeddingLatencyAlpha:     8.02e-6,
+		EmbeddingLatencyBeta:      2.23e-3,
+		EmbeddingLatencyStdev:     7.00e-8,
+		ImageLatencyAlpha:         3.30e-2,
+		ImageLatencyBeta:          3.00e-1,
+		ImageLatencyStdev:         2.00e-3,
+		TranscriptionLatencyAlpha: 8.00e-3,
+		TranscriptionLatencyBeta:  4.00e-3,
+		TranscriptionLatencyStdev: 1.00e-3,
+		SpeechLatencyAlpha:        5.00e-3,
+		SpeechLatencyBeta:         2.00e-3,
+		SpeechLatencyStdev:        1.00e-3,
+		PowerKW:                   0.4,
+		AvailMemoryGB:             80,
+		EmbodiedImpactADPe:        5.1e-3,
+		EmbodiedImpactGWP:         143,
+		EmbodiedImpactPE:          1828,
 	}
 
 	t.Run("should return default GPU values", func(t *testing.T) {
@@ -38,16 +60,38 @@ func TestGenericServerInfra(t *testing.T) {
 			HardwareLifespan:   5 * 365 * 24 * 60 * 60,
 			DatacenterPUE:      1.2,
 			GPUModel: GPU{
-				EnergyAlpha:        8.91e-8,
-				EnergyBeta:         1.43e-6,
-				EnergyStdev:        5.19e-7,
-				LatencyAlpha:       8.02e-4,
-				LatencyBeta:        2.23e-2,
-				LatencyStdev:       7.00e-6,
-				AvailMemoryGB:      80,
-				EmbodiedImpactADPe: 5.1e-3,
-				EmbodiedImpactGWP:  143,
-				EmbodiedImpactPE:   1828,
+				EnergyAlpha:               8.91e-8,
+				EnergyBeta:                1.43e-6,
+				EnergyStdev:               5.19e-7,
+				LatencyAlpha:              8.02e-4,
+				LatencyBeta:               2.23e-2,
+				LatencyStdev:              7.00e-6,
+				PrefillEnergyAlpha:        2.00e-10,
+				PrefillEnergyBeta:         5.00e-9,
+				PrefillEnergyStdev:        1.00e-10,
+				PrefillLatencyAlpha:       2.00e-6,
+				PrefillLatencyBeta:        2.00e-5,
+				PrefillLatencyStdev:       1.00e-7,
+				EmbeddingEnergyAlpha:      8.91e-10,
+				EmbeddingEnergyBeta:       1.43e-8,
+				EmbeddingEnergyStdev:      5.19e-10,
+				EmbeddingLatencyAlpha:     8.02e-6,
+				EmbeddingLatencyBeta:      2.23e-3,
+				EmbeddingLatencyStdev:     7.00e-8,
+				ImageLatencyAlpha:         3.30e-2,
+				ImageLatencyBeta:          3.00e-1,
+				ImageLatencyStdev:         2.00e-3,
+				TranscriptionLatencyAlpha: 8.00e-3,
+				TranscriptionLatencyBeta:  4.00e-3,
+				TranscriptionLatencyStdev: 1.00e-3,
+				SpeechLatencyAlpha:        5.00e-3,
+				SpeechLatencyBeta:         2.00e-3,
+				SpeechLatencyStdev:        1.00e-3,
+				PowerKW:                   0.4,
+				AvailMemoryGB:             80,
+				EmbodiedImpactADPe:        5.1e-3,
+				EmbodiedImpactGWP:         143,
+				EmbodiedImpactPE:          1828,
 			},
 		}
 		got, err := GenericGPUServer()
@@ -271,7 +315,7 @@ func TestServerInfra_GPUEnergy(t *testing.T) {
 	}{
 		{
 			// perTokenMean: 8.91e-8 * 10 + 1.43e-6 = 0.000002321
-			// gpuEnergyMin: 100 * (0.000002321 - 1.96 * 5.19e-7) = 0.00013037599999999997
+			// gpuEnergyMin: 100 * (0.000002321 - 1.96 * 5.19e-7) = 0.000130376
 			// gpuEnergyMax: 100 * (0.000002321 + 1.96 * 5.19e-7) = 0.000333824
 			name: "should calculate GPU energy successfully",
 			fields: fields{
@@ -286,7 +330,7 @@ func TestServerInfra_GPUEnergy(t *testing.T) {
 				outputTokenCount:      100,
 			},
 			want: common.RangeValue{
-				Min: 0.00013037599999999997,
+				Min: 0.000130376,
 				Max: 0.000333824,
 			},
 			expectedError: nil,
@@ -371,8 +415,11 @@ func TestServerInfra_GPUEnergy(t *testing.T) {
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
 			} else {
+				// Compare with a tolerance: the rounding of the last digit depends on whether the platform
+				// fuses multiply-adds.
 				assert.NoError(t, err)
-				assert.Equal(t, tt.want, got)
+				assert.InDelta(t, tt.want.Min, got.Min, 1e-15)
+				assert.InDelta(t, tt.want.Max, got.Max, 1e-15)
 			}
 		})
 	}
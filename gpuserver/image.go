@@ -0,0 +1,63 @@
+package gpuserver
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/omegabytes/ecologits-go/common"
+)
+
+// ImageGenerationLatency returns the time in seconds a GPU is busy generating imageCount images of the given
+// size in megapixels with steps denoising steps each, clamped to the observed request latency like
+// GenerationLatency.
+func (g *GPUServer) ImageGenerationLatency(
+	modelActiveParamCount float64,
+	imageCount int,
+	megapixels float64,
+	steps int,
+	requestLatencySecs float64,
+) (common.RangeValue, error) {
+	if modelActiveParamCount <= 0 {
+		return common.RangeValue{}, fmt.Errorf("modelActiveParamCount must be greater than 0")
+	}
+	if imageCount <= 0 {
+		return common.RangeValue{}, fmt.Errorf("imageCount must be greater than 0")
+	}
+	if megapixels <= 0 {
+		return common.RangeValue{}, fmt.Errorf("megapixels must be greater than 0")
+	}
+	if steps <= 0 {
+		return common.RangeValue{}, fmt.Errorf("steps must be greater than 0")
+	}
+	if requestLatencySecs <= 0 {
+		return common.RangeValue{}, fmt.Errorf("requestLatencySecs must be greater than 0")
+	}
+	gpu := g.GPUModel
+	if gpu.ImageLatencyAlpha <= 0 || gpu.ImageLatencyBeta <= 0 || gpu.ImageLatencyStdev <= 0 {
+		return common.RangeValue{}, fmt.Errorf("GPU image latency parameters must be greater than 0")
+	}
+	stepCount := float64(imageCount * steps)
+	gpuLatencyMean := stepCount*megapixels*gpu.ImageLatencyAlpha*modelActiveParamCount +
+		float64(imageCount)*gpu.ImageLatencyBeta
+	gpuLatencyInterval := stepCount * megapixels * 1.96 * gpu.ImageLatencyStdev
+	imageLatency := common.RangeValue{
+		Min: math.Max(0, gpuLatencyMean-gpuLatencyInterval),
+		Max: gpuLatencyMean + gpuLatencyInterval,
+	}
+	return clampLatency(imageLatency, requestLatencySecs), nil
+}
+
+// GPUBusyEnergyKWH returns the energy consumption of a single GPU in kWh while it is busy for gpuLatencySecs,
+// for workloads that are modeled by GPU time rather than per-token energy.
+func (g *GPUServer) GPUBusyEnergyKWH(gpuLatencySecs common.RangeValue) (common.RangeValue, error) {
+	if gpuLatencySecs.Min < 0 || gpuLatencySecs.Max <= 0 {
+		return common.RangeValue{}, fmt.Errorf("gpuLatencySecs must be greater than 0")
+	}
+	if g.GPUModel.PowerKW <= 0 {
+		return common.RangeValue{}, fmt.Errorf("GPU power must be greater than 0")
+	}
+	return common.RangeValue{
+		Min: gpuLatencySecs.Min / 3600 * g.GPUModel.PowerKW,
+		Max: gpuLatencySecs.Max / 3600 * g.GPUModel.PowerKW,
+	}, nil
+}
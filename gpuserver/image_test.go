@@ -0,0 +1,123 @@
+package gpuserver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerInfra_ImageGenerationLatency(t *testing.T) {
+	imageGPU := GPU{
+		ImageLatencyAlpha: 3.30e-2,
+		ImageLatencyBeta:  3.00e-1,
+		ImageLatencyStdev: 2.00e-3,
+	}
+	type args struct {
+		modelActiveParamCount float64
+		imageCount            int
+		megapixels            float64
+		steps                 int
+		requestLatencySecs    float64
+	}
+	tests := []struct {
+		name          string
+		gpu           GPU
+		args          args
+		want          common.RangeValue
+		expectedError error
+	}{
+		{
+			// gpuLatencyMean: 2 * 30 * 1 * 3.30e-2 * 2 + 2 * 3.00e-1 = 4.56
+			// gpuLatencyInterval: 2 * 30 * 1 * 1.96 * 2.00e-3 = 0.2352
+			name: "should calculate image generation latency successfully",
+			gpu:  imageGPU,
+			args: args{modelActiveParamCount: 2, imageCount: 2, megapixels: 1, steps: 30, requestLatencySecs: 10},
+			want: common.RangeValue{Min: 4.3248, Max: 4.7952},
+		},
+		{
+			name: "should return requestLatencySecs when it is shorter than the estimate",
+			gpu:  imageGPU,
+			args: args{modelActiveParamCount: 2, imageCount: 2, megapixels: 1, steps: 30, requestLatencySecs: 4},
+			want: common.RangeValue{Min: 4, Max: 4},
+		},
+		{
+			name:          "should return error when imageCount is 0",
+			gpu:           imageGPU,
+			args:          args{modelActiveParamCount: 2, megapixels: 1, steps: 30, requestLatencySecs: 4},
+			expectedError: fmt.Errorf("imageCount must be greater than 0"),
+		},
+		{
+			name:          "should return error when megapixels is 0",
+			gpu:           imageGPU,
+			args:          args{modelActiveParamCount: 2, imageCount: 1, steps: 30, requestLatencySecs: 4},
+			expectedError: fmt.Errorf("megapixels must be greater than 0"),
+		},
+		{
+			name:          "should return error when steps is 0",
+			gpu:           imageGPU,
+			args:          args{modelActiveParamCount: 2, imageCount: 1, megapixels: 1, requestLatencySecs: 4},
+			expectedError: fmt.Errorf("steps must be greater than 0"),
+		},
+		{
+			name:          "should return error when GPU image latency parameters are invalid",
+			args:          args{modelActiveParamCount: 2, imageCount: 1, megapixels: 1, steps: 30, requestLatencySecs: 4},
+			expectedError: fmt.Errorf("GPU image latency parameters must be greater than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GPUServer{GPUModel: tt.gpu}
+			got, err := s.ImageGenerationLatency(tt.args.modelActiveParamCount, tt.args.imageCount,
+				tt.args.megapixels, tt.args.steps, tt.args.requestLatencySecs)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.Min, got.Min, 1e-9)
+			assert.InDelta(t, tt.want.Max, got.Max, 1e-9)
+		})
+	}
+}
+
+func TestServerInfra_GPUBusyEnergyKWH(t *testing.T) {
+	tests := []struct {
+		name          string
+		powerKW       float64
+		latency       common.RangeValue
+		want          common.RangeValue
+		expectedError error
+	}{
+		{
+			name:    "should convert GPU time to energy",
+			powerKW: 0.4,
+			latency: common.RangeValue{Min: 9, Max: 18},
+			want:    common.RangeValue{Min: 0.001, Max: 0.002},
+		},
+		{
+			name:          "should return error when latency is 0",
+			powerKW:       0.4,
+			expectedError: fmt.Errorf("gpuLatencySecs must be greater than 0"),
+		},
+		{
+			name:          "should return error when GPU power is 0",
+			latency:       common.RangeValue{Min: 9, Max: 18},
+			expectedError: fmt.Errorf("GPU power must be greater than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GPUServer{GPUModel: GPU{PowerKW: tt.powerKW}}
+			got, err := s.GPUBusyEnergyKWH(tt.latency)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.Min, got.Min, 1e-12)
+			assert.InDelta(t, tt.want.Max, got.Max, 1e-12)
+		})
+	}
+}
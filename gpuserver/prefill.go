@@ -0,0 +1,85 @@
+package gpuserver
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/omegabytes/ecologits-go/common"
+)
+
+// PrefillEnergyKWH returns the 95% confidence interval of the energy consumption of a single GPU in kWh to
+// process inputTokenCount uncached prompt tokens before generation starts.
+func (g *GPUServer) PrefillEnergyKWH(
+	modelActiveParamCount float64,
+	inputTokenCount float64,
+) (common.RangeValue, error) {
+	if err := validatePrefillArgs(modelActiveParamCount, inputTokenCount); err != nil {
+		return common.RangeValue{}, err
+	}
+	gpu := g.GPUModel
+	if gpu.PrefillEnergyAlpha <= 0 || gpu.PrefillEnergyBeta <= 0 || gpu.PrefillEnergyStdev <= 0 {
+		return common.RangeValue{}, fmt.Errorf("GPU prefill energy parameters must be greater than 0")
+	}
+	gpuEnergyPerTokenMean := gpu.PrefillEnergyAlpha*modelActiveParamCount + gpu.PrefillEnergyBeta
+	return common.RangeValue{
+		Min: math.Max(0, inputTokenCount*(gpuEnergyPerTokenMean-1.96*gpu.PrefillEnergyStdev)),
+		Max: inputTokenCount * (gpuEnergyPerTokenMean + 1.96*gpu.PrefillEnergyStdev),
+	}, nil
+}
+
+// PrefillLatency returns the 95% confidence interval of the time in seconds to process inputTokenCount
+// uncached prompt tokens. Unlike GenerationLatency it is not clamped; see InferenceLatency.
+func (g *GPUServer) PrefillLatency(
+	modelActiveParamCount float64,
+	inputTokenCount float64,
+) (common.RangeValue, error) {
+	if err := validatePrefillArgs(modelActiveParamCount, inputTokenCount); err != nil {
+		return common.RangeValue{}, err
+	}
+	gpu := g.GPUModel
+	if gpu.PrefillLatencyAlpha <= 0 || gpu.PrefillLatencyBeta <= 0 || gpu.PrefillLatencyStdev <= 0 {
+		return common.RangeValue{}, fmt.Errorf("GPU prefill latency parameters must be greater than 0")
+	}
+	gpuLatencyPerTokenMean := gpu.PrefillLatencyAlpha*modelActiveParamCount + gpu.PrefillLatencyBeta
+	return common.RangeValue{
+		Min: math.Max(0, inputTokenCount*(gpuLatencyPerTokenMean-1.96*gpu.PrefillLatencyStdev)),
+		Max: inputTokenCount * (gpuLatencyPerTokenMean + 1.96*gpu.PrefillLatencyStdev),
+	}, nil
+}
+
+// InferenceLatency returns the time in seconds a GPU is busy prefilling inputTokenCount uncached prompt tokens
+// and then generating outputTokenCount tokens, clamped to the observed request latency. Without input tokens
+// it equals GenerationLatency.
+func (g *GPUServer) InferenceLatency(
+	modelActiveParamCount float64,
+	inputTokenCount float64,
+	outputTokenCount float64,
+	requestLatencySecs float64,
+) (common.RangeValue, error) {
+	if inputTokenCount < 0 {
+		return common.RangeValue{}, fmt.Errorf("inputTokenCount cannot be negative")
+	}
+	generationLatency, err := g.GenerationLatency(modelActiveParamCount, outputTokenCount, requestLatencySecs)
+	if err != nil || inputTokenCount == 0 {
+		return generationLatency, err
+	}
+	prefillLatency, err := g.PrefillLatency(modelActiveParamCount, inputTokenCount)
+	if err != nil {
+		return common.RangeValue{}, err
+	}
+	decodeLatency := g.decodeLatency(modelActiveParamCount, outputTokenCount)
+	return clampLatency(common.RangeValue{
+		Min: prefillLatency.Min + decodeLatency.Min,
+		Max: prefillLatency.Max + decodeLatency.Max,
+	}, requestLatencySecs), nil
+}
+
+func validatePrefillArgs(modelActiveParamCount float64, inputTokenCount float64) error {
+	if modelActiveParamCount <= 0 {
+		return fmt.Errorf("modelActiveParamCount must be greater than 0")
+	}
+	if inputTokenCount <= 0 {
+		return fmt.Errorf("inputTokenCount must be greater than 0")
+	}
+	return nil
+}
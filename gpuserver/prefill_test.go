@@ -0,0 +1,123 @@
+package gpuserver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func prefillGPU() GPU {
+	return GPU{
+		LatencyAlpha:        8.02e-4,
+		LatencyBeta:         2.23e-2,
+		LatencyStdev:        7.00e-6,
+		PrefillEnergyAlpha:  2.00e-10,
+		PrefillEnergyBeta:   5.00e-9,
+		PrefillEnergyStdev:  1.00e-10,
+		PrefillLatencyAlpha: 2.00e-6,
+		PrefillLatencyBeta:  2.00e-5,
+		PrefillLatencyStdev: 1.00e-7,
+	}
+}
+
+func TestServerInfra_PrefillEnergyKWH(t *testing.T) {
+	tests := []struct {
+		name            string
+		gpu             GPU
+		inputTokenCount float64
+		want            common.RangeValue
+		expectedError   error
+	}{
+		{
+			// gpuEnergyPerTokenMean: 2.00e-10 * 10 + 5.00e-9 = 7.0e-9
+			// gpuEnergyMin: 1000 * (7.0e-9 - 1.96 * 1.00e-10) = 6.804e-6
+			// gpuEnergyMax: 1000 * (7.0e-9 + 1.96 * 1.00e-10) = 7.196e-6
+			name:            "should calculate prefill energy successfully",
+			gpu:             prefillGPU(),
+			inputTokenCount: 1000,
+			want:            common.RangeValue{Min: 6.804e-6, Max: 7.196e-6},
+		},
+		{
+			name:            "should return error when inputTokenCount is 0",
+			gpu:             prefillGPU(),
+			inputTokenCount: 0,
+			expectedError:   fmt.Errorf("inputTokenCount must be greater than 0"),
+		},
+		{
+			name:            "should return error when GPU prefill energy parameters are invalid",
+			inputTokenCount: 1000,
+			expectedError:   fmt.Errorf("GPU prefill energy parameters must be greater than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GPUServer{GPUModel: tt.gpu}
+			got, err := s.PrefillEnergyKWH(10, tt.inputTokenCount)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.Min, got.Min, 1e-15)
+			assert.InDelta(t, tt.want.Max, got.Max, 1e-15)
+		})
+	}
+}
+
+func TestServerInfra_InferenceLatency(t *testing.T) {
+	type args struct {
+		inputTokenCount    float64
+		outputTokenCount   float64
+		requestLatencySecs float64
+	}
+	tests := []struct {
+		name          string
+		args          args
+		want          common.RangeValue
+		expectedError error
+	}{
+		{
+			// decode: 100 * (8.02e-4 * 10 + 2.23e-2 ± 1.96 * 7.00e-6) = [3.030628, 3.033372]
+			name: "should equal the generation latency without input tokens",
+			args: args{outputTokenCount: 100, requestLatencySecs: 5},
+			want: common.RangeValue{Min: 3.030628, Max: 3.033372},
+		},
+		{
+			// prefill: 10000 * (2.00e-6 * 10 + 2.00e-5 ± 1.96 * 1.00e-7) = [0.39804, 0.40196]
+			name: "should add the prefill latency of input tokens",
+			args: args{inputTokenCount: 10000, outputTokenCount: 100, requestLatencySecs: 5},
+			want: common.RangeValue{Min: 3.428668, Max: 3.435332},
+		},
+		{
+			name: "should clamp prefill and generation to the request latency",
+			args: args{inputTokenCount: 10000, outputTokenCount: 100, requestLatencySecs: 3.2},
+			want: common.RangeValue{Min: 3.2, Max: 3.2},
+		},
+		{
+			name:          "should return error when inputTokenCount is negative",
+			args:          args{inputTokenCount: -1, outputTokenCount: 100, requestLatencySecs: 5},
+			expectedError: fmt.Errorf("inputTokenCount cannot be negative"),
+		},
+		{
+			name:          "should return error when outputTokenCount is 0",
+			args:          args{inputTokenCount: 10000, requestLatencySecs: 5},
+			expectedError: fmt.Errorf("outputTokenCount must be greater than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GPUServer{AvailableGPUCount: 4, PowerConsumptionKW: 1.5, GPUModel: prefillGPU()}
+			got, err := s.InferenceLatency(10, tt.args.inputTokenCount, tt.args.outputTokenCount,
+				tt.args.requestLatencySecs)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.Min, got.Min, 1e-9)
+			assert.InDelta(t, tt.want.Max, got.Max, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package gpuserver
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/omegabytes/ecologits-go/common"
+)
+
+// TranscriptionLatency returns the time in seconds a GPU is busy transcribing audioSecs seconds of audio,
+// clamped to the observed request latency like GenerationLatency.
+func (g *GPUServer) TranscriptionLatency(
+	modelActiveParamCount float64,
+	audioSecs float64,
+	requestLatencySecs float64,
+) (common.RangeValue, error) {
+	if audioSecs <= 0 {
+		return common.RangeValue{}, fmt.Errorf("audioSecs must be greater than 0")
+	}
+	gpu := g.GPUModel
+	if gpu.TranscriptionLatencyAlpha <= 0 || gpu.TranscriptionLatencyBeta <= 0 ||
+		gpu.TranscriptionLatencyStdev <= 0 {
+		return common.RangeValue{}, fmt.Errorf("GPU transcription latency parameters must be greater than 0")
+	}
+	return realTimeLatency(modelActiveParamCount, audioSecs, requestLatencySecs,
+		gpu.TranscriptionLatencyAlpha, gpu.TranscriptionLatencyBeta, gpu.TranscriptionLatencyStdev)
+}
+
+// SpeechLatency returns the time in seconds a GPU is busy synthesizing speech for characterCount characters of
+// text, clamped to the observed request latency like GenerationLatency.
+func (g *GPUServer) SpeechLatency(
+	modelActiveParamCount float64,
+	characterCount float64,
+	requestLatencySecs float64,
+) (common.RangeValue, error) {
+	if characterCount <= 0 {
+		return common.RangeValue{}, fmt.Errorf("characterCount must be greater than 0")
+	}
+	gpu := g.GPUModel
+	if gpu.SpeechLatencyAlpha <= 0 || gpu.SpeechLatencyBeta <= 0 || gpu.SpeechLatencyStdev <= 0 {
+		return common.RangeValue{}, fmt.Errorf("GPU speech latency parameters must be greater than 0")
+	}
+	return realTimeLatency(modelActiveParamCount, characterCount, requestLatencySecs,
+		gpu.SpeechLatencyAlpha, gpu.SpeechLatencyBeta, gpu.SpeechLatencyStdev)
+}
+
+// realTimeLatency returns the 95% confidence interval of the GPU time spent on units of work, each taking
+// alpha * modelActiveParamCount + beta seconds.
+func realTimeLatency(
+	modelActiveParamCount float64,
+	units float64,
+	requestLatencySecs float64,
+	alpha, beta, stdev float64,
+) (common.RangeValue, error) {
+	if modelActiveParamCount <= 0 {
+		return common.RangeValue{}, fmt.Errorf("modelActiveParamCount must be greater than 0")
+	}
+	if requestLatencySecs <= 0 {
+		return common.RangeValue{}, fmt.Errorf("requestLatencySecs must be greater than 0")
+	}
+	latencyPerUnitMean := alpha*modelActiveParamCount + beta
+	latencyInterval := common.RangeValue{
+		Min: math.Max(0, units*(latencyPerUnitMean-1.96*stdev)),
+		Max: units * (latencyPerUnitMean + 1.96*stdev),
+	}
+	return clampLatency(latencyInterval, requestLatencySecs), nil
+}
@@ -0,0 +1,131 @@
+package gpuserver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerInfra_TranscriptionLatency(t *testing.T) {
+	speechGPU := GPU{
+		TranscriptionLatencyAlpha: 8.00e-3,
+		TranscriptionLatencyBeta:  4.00e-3,
+		TranscriptionLatencyStdev: 1.00e-3,
+	}
+	type args struct {
+		modelActiveParamCount float64
+		audioSecs             float64
+		requestLatencySecs    float64
+	}
+	tests := []struct {
+		name          string
+		gpu           GPU
+		args          args
+		want          common.RangeValue
+		expectedError error
+	}{
+		{
+			// latencyPerUnitMean: 8.00e-3 * 1.5 + 4.00e-3 = 0.016
+			// latencyMin: 60 * (0.016 - 1.96 * 1.00e-3) = 0.8424
+			// latencyMax: 60 * (0.016 + 1.96 * 1.00e-3) = 1.0776
+			name: "should calculate transcription latency successfully",
+			gpu:  speechGPU,
+			args: args{modelActiveParamCount: 1.5, audioSecs: 60, requestLatencySecs: 5},
+			want: common.RangeValue{Min: 0.8424, Max: 1.0776},
+		},
+		{
+			name: "should return requestLatencySecs when it is shorter than the estimate",
+			gpu:  speechGPU,
+			args: args{modelActiveParamCount: 1.5, audioSecs: 60, requestLatencySecs: 1},
+			want: common.RangeValue{Min: 1, Max: 1},
+		},
+		{
+			name:          "should return error when audioSecs is 0",
+			gpu:           speechGPU,
+			args:          args{modelActiveParamCount: 1.5, requestLatencySecs: 1},
+			expectedError: fmt.Errorf("audioSecs must be greater than 0"),
+		},
+		{
+			name:          "should return error when modelActiveParamCount is 0",
+			gpu:           speechGPU,
+			args:          args{audioSecs: 60, requestLatencySecs: 1},
+			expectedError: fmt.Errorf("modelActiveParamCount must be greater than 0"),
+		},
+		{
+			name:          "should return error when GPU transcription latency parameters are invalid",
+			args:          args{modelActiveParamCount: 1.5, audioSecs: 60, requestLatencySecs: 1},
+			expectedError: fmt.Errorf("GPU transcription latency parameters must be greater than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GPUServer{GPUModel: tt.gpu}
+			got, err := s.TranscriptionLatency(tt.args.modelActiveParamCount, tt.args.audioSecs,
+				tt.args.requestLatencySecs)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.Min, got.Min, 1e-9)
+			assert.InDelta(t, tt.want.Max, got.Max, 1e-9)
+		})
+	}
+}
+
+func TestServerInfra_SpeechLatency(t *testing.T) {
+	speechGPU := GPU{
+		SpeechLatencyAlpha: 5.00e-3,
+		SpeechLatencyBeta:  2.00e-3,
+		SpeechLatencyStdev: 1.00e-3,
+	}
+	type args struct {
+		modelActiveParamCount float64
+		characterCount        float64
+		requestLatencySecs    float64
+	}
+	tests := []struct {
+		name          string
+		gpu           GPU
+		args          args
+		want          common.RangeValue
+		expectedError error
+	}{
+		{
+			// latencyPerUnitMean: 5.00e-3 * 1 + 2.00e-3 = 0.007
+			// latencyMin: 300 * (0.007 - 1.96 * 1.00e-3) = 1.512
+			// latencyMax: 300 * (0.007 + 1.96 * 1.00e-3) = 2.688
+			name: "should calculate speech latency successfully",
+			gpu:  speechGPU,
+			args: args{modelActiveParamCount: 1, characterCount: 300, requestLatencySecs: 5},
+			want: common.RangeValue{Min: 1.512, Max: 2.688},
+		},
+		{
+			name:          "should return error when characterCount is 0",
+			gpu:           speechGPU,
+			args:          args{modelActiveParamCount: 1, requestLatencySecs: 5},
+			expectedError: fmt.Errorf("characterCount must be greater than 0"),
+		},
+		{
+			name:          "should return error when GPU speech latency parameters are invalid",
+			args:          args{modelActiveParamCount: 1, characterCount: 300, requestLatencySecs: 5},
+			expectedError: fmt.Errorf("GPU speech latency parameters must be greater than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GPUServer{GPUModel: tt.gpu}
+			got, err := s.SpeechLatency(tt.args.modelActiveParamCount, tt.args.characterCount,
+				tt.args.requestLatencySecs)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.Min, got.Min, 1e-9)
+			assert.InDelta(t, tt.want.Max, got.Max, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package gpuserver
+
+import (
+	"fmt"
+
+	"github.com/omegabytes/ecologits-go/common"
+)
+
+// StreamTiming is the timing measured for a streamed response. Zero values are not measured.
+type StreamTiming struct {
+	// TimeToFirstTokenSecs is the time in seconds between sending the request and receiving the first token.
+	TimeToFirstTokenSecs float64
+	// InterTokenLatencySecs is the mean time in seconds between two consecutive output tokens.
+	InterTokenLatencySecs float64
+}
+
+// StreamingLatency returns the time in seconds a GPU is busy with a streamed request, which InferenceLatency can
+// only bound by the whole request latency. The prefill and the first output token are bounded by the time to
+// first token, the remaining output tokens by the inter-token latency, and the sum by the request latency.
+func (g *GPUServer) StreamingLatency(
+	modelActiveParamCount float64,
+	inputTokenCount float64,
+	outputTokenCount float64,
+	requestLatencySecs float64,
+	timing StreamTiming,
+) (common.RangeValue, error) {
+	if timing.TimeToFirstTokenSecs < 0 {
+		return common.RangeValue{}, fmt.Errorf("timeToFirstTokenSecs cannot be negative")
+	}
+	if timing.InterTokenLatencySecs < 0 {
+		return common.RangeValue{}, fmt.Errorf("interTokenLatencySecs cannot be negative")
+	}
+	if timing.TimeToFirstTokenSecs > requestLatencySecs {
+		return common.RangeValue{}, fmt.Errorf("timeToFirstTokenSecs cannot exceed requestLatencySecs")
+	}
+	if inputTokenCount < 0 {
+		return common.RangeValue{}, fmt.Errorf("inputTokenCount cannot be negative")
+	}
+	if _, err := g.GenerationLatency(modelActiveParamCount, outputTokenCount, requestLatencySecs); err != nil {
+		return common.RangeValue{}, err
+	}
+
+	firstTokenLatency := g.decodeLatency(modelActiveParamCount, 1)
+	if inputTokenCount > 0 {
+		prefillLatency, err := g.PrefillLatency(modelActiveParamCount, inputTokenCount)
+		if err != nil {
+			return common.RangeValue{}, err
+		}
+		firstTokenLatency.Min += prefillLatency.Min
+		firstTokenLatency.Max += prefillLatency.Max
+	}
+	if timing.TimeToFirstTokenSecs > 0 {
+		firstTokenLatency = clampLatency(firstTokenLatency, timing.TimeToFirstTokenSecs)
+	}
+
+	var nextTokensLatency common.RangeValue
+	if outputTokenCount > 1 {
+		nextTokensLatency = g.decodeLatency(modelActiveParamCount, outputTokenCount-1)
+		if timing.InterTokenLatencySecs > 0 {
+			nextTokensLatency = clampLatency(nextTokensLatency, timing.InterTokenLatencySecs*(outputTokenCount-1))
+		}
+	}
+
+	return clampLatency(common.RangeValue{
+		Min: firstTokenLatency.Min + nextTokensLatency.Min,
+		Max: firstTokenLatency.Max + nextTokensLatency.Max,
+	}, requestLatencySecs), nil
+}
@@ -0,0 +1,113 @@
+package gpuserver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerInfra_StreamingLatency(t *testing.T) {
+	type args struct {
+		inputTokenCount    float64
+		outputTokenCount   float64
+		requestLatencySecs float64
+		timing             StreamTiming
+	}
+	tests := []struct {
+		name          string
+		args          args
+		want          common.RangeValue
+		expectedError error
+	}{
+		{
+			// decode: 100 * (8.02e-4 * 10 + 2.23e-2 ± 1.96 * 7.00e-6) = [3.030628, 3.033372]
+			name: "should equal the generation latency of unary calls",
+			args: args{outputTokenCount: 100, requestLatencySecs: 5},
+			want: common.RangeValue{Min: 3.030628, Max: 3.033372},
+		},
+		{
+			name: "should clamp unary calls to the request latency",
+			args: args{outputTokenCount: 100, requestLatencySecs: 1},
+			want: common.RangeValue{Min: 1, Max: 1},
+		},
+		{
+			// first token: 0.03032 ± 1.372e-5, next tokens: 99 * 0.02 = 1.98
+			name: "should bound the next tokens by the inter-token latency",
+			args: args{
+				outputTokenCount:   100,
+				requestLatencySecs: 5,
+				timing:             StreamTiming{TimeToFirstTokenSecs: 0.5, InterTokenLatencySecs: 0.02},
+			},
+			want: common.RangeValue{Min: 2.01030628, Max: 2.01033372},
+		},
+		{
+			// first token: prefill [0.39804, 0.40196] + 0.03032 ± 1.372e-5, clamped to 0.3
+			// next tokens: 99 * (0.03032 ± 1.372e-5) = [3.00032172, 3.00303828]
+			name: "should bound the prefill and first token by the time to first token",
+			args: args{
+				inputTokenCount:    10000,
+				outputTokenCount:   100,
+				requestLatencySecs: 10,
+				timing:             StreamTiming{TimeToFirstTokenSecs: 0.3},
+			},
+			want: common.RangeValue{Min: 3.30032172, Max: 3.30303828},
+		},
+		{
+			name: "should bound a single token by the time to first token",
+			args: args{
+				outputTokenCount:   1,
+				requestLatencySecs: 1,
+				timing:             StreamTiming{TimeToFirstTokenSecs: 0.01, InterTokenLatencySecs: 0.02},
+			},
+			want: common.RangeValue{Min: 0.01, Max: 0.01},
+		},
+		{
+			name: "should still clamp streamed calls to the request latency",
+			args: args{
+				outputTokenCount:   100,
+				requestLatencySecs: 1,
+				timing:             StreamTiming{TimeToFirstTokenSecs: 0.5, InterTokenLatencySecs: 0.02},
+			},
+			want: common.RangeValue{Min: 1, Max: 1},
+		},
+		{
+			name: "should return error when the time to first token exceeds the request latency",
+			args: args{
+				outputTokenCount:   100,
+				requestLatencySecs: 1,
+				timing:             StreamTiming{TimeToFirstTokenSecs: 2},
+			},
+			expectedError: fmt.Errorf("timeToFirstTokenSecs cannot exceed requestLatencySecs"),
+		},
+		{
+			name: "should return error when the inter-token latency is negative",
+			args: args{
+				outputTokenCount:   100,
+				requestLatencySecs: 1,
+				timing:             StreamTiming{InterTokenLatencySecs: -0.02},
+			},
+			expectedError: fmt.Errorf("interTokenLatencySecs cannot be negative"),
+		},
+		{
+			name:          "should return error when outputTokenCount is 0",
+			args:          args{requestLatencySecs: 1, timing: StreamTiming{TimeToFirstTokenSecs: 0.5}},
+			expectedError: fmt.Errorf("outputTokenCount must be greater than 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &GPUServer{AvailableGPUCount: 4, PowerConsumptionKW: 1.5, GPUModel: prefillGPU()}
+			got, err := s.StreamingLatency(10, tt.args.inputTokenCount, tt.args.outputTokenCount,
+				tt.args.requestLatencySecs, tt.args.timing)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.want.Min, got.Min, 1e-9)
+			assert.InDelta(t, tt.want.Max, got.Max, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package impact
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+	"github.com/omegabytes/ecologits-go/gpuserver"
+	"github.com/omegabytes/ecologits-go/request"
+)
+
+// ComputeEmbeddingImpacts computes the environmental and energy impact of an embedding call from its input
+// token count and batch size. It returns an error wrapping ErrUnsupportedTask for models that are not
+// embedding models. The GPU coefficients for embedding are placeholders, which the estimate reports with a
+// WarningGPUCoefficientsPlaceholder warning.
+func ComputeEmbeddingImpacts(
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	req request.EmbeddingRequest,
+) (Impacts, error) {
+	return ComputeEmbeddingImpactsWithMixProvider(context.Background(), nil, aiModel, server, req)
+}
+
+// ComputeEmbeddingImpactsWithMixProvider is ComputeEmbeddingImpacts priced at the electricity mix that mixes
+// returns for the geo and timestamp of req, eg to account for a nightly indexing batch at the carbon intensity of
+// the hours it ran. A nil provider uses the average mixes of the default database.
+func ComputeEmbeddingImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	req request.EmbeddingRequest,
+) (Impacts, error) {
+	if err := checkTask(aiModel, aimodel.TaskEmbeddings); err != nil {
+		return Impacts{}, err
+	}
+	paramsActiveMax := aiModel.Architecture().Parameters.Active.Max
+	embeddingLatency, err := server.EmbeddingLatency(paramsActiveMax, req.InputTokenCount, req.BatchSize,
+		req.Latency.Seconds())
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get embedding latency: %w", err)
+	}
+
+	gpuEnergyKWH, err := server.EmbeddingEnergyKWH(paramsActiveMax, req.InputTokenCount, req.BatchSize)
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get GPU energy: %w", err)
+	}
+	return placeholderImpacts(ctx, mixes, aiModel, server, req.Location, embeddingLatency, gpuEnergyKWH,
+		"embedding")
+}
@@ -0,0 +1,51 @@
+package impact
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+	"github.com/omegabytes/ecologits-go/gpuserver"
+	"github.com/omegabytes/ecologits-go/request"
+)
+
+// ComputeImageImpacts computes the environmental and energy impact of an image generation call from the
+// number of images, their resolution and the number of diffusion steps. Energy is derived from the GPU time
+// spent denoising. It returns an error wrapping ErrUnsupportedTask for models that are not image generation
+// models. The GPU coefficients for image generation are placeholders, which the estimate reports with a
+// WarningGPUCoefficientsPlaceholder warning.
+func ComputeImageImpacts(
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	req request.ImageRequest,
+) (Impacts, error) {
+	return ComputeImageImpactsWithMixProvider(context.Background(), nil, aiModel, server, req)
+}
+
+// ComputeImageImpactsWithMixProvider is ComputeImageImpacts priced at the electricity mix that mixes returns
+// where and when the images were generated. The mix only changes the usage impacts, the embodied impacts depend
+// on the GPU time alone. A nil provider uses the average mixes of the default database.
+func ComputeImageImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	req request.ImageRequest,
+) (Impacts, error) {
+	if err := checkTask(aiModel, aimodel.TaskImageGeneration); err != nil {
+		return Impacts{}, err
+	}
+	paramsActiveMax := aiModel.Architecture().Parameters.Active.Max
+	imageLatency, err := server.ImageGenerationLatency(paramsActiveMax, req.ImageCount, req.Megapixels(),
+		req.DiffusionSteps(), req.Latency.Seconds())
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get image generation latency: %w", err)
+	}
+
+	gpuEnergyKWH, err := server.GPUBusyEnergyKWH(imageLatency)
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get GPU energy: %w", err)
+	}
+	return placeholderImpacts(ctx, mixes, aiModel, server, req.Location, imageLatency, gpuEnergyKWH,
+		"image generation")
+}
@@ -4,7 +4,11 @@ Package impact provides utilities for calculating the environmental and energy i
 package impact
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/omegabytes/ecologits-go/aimodel"
 	"github.com/omegabytes/ecologits-go/common"
@@ -40,33 +44,154 @@ type ImpactIface interface {
 
 type Impacts struct {
 	Energy common.RangeValue
-	ADPe   ADPe
-	GWP    GWP
-	PE     PE
+	// PrefillEnergy and DecodeEnergy split the GPU energy of a chat request in kWh between processing the
+	// prompt and generating the output, including datacenter overhead. Energy additionally includes the
+	// baseline consumption of the server.
+	PrefillEnergy common.RangeValue
+	DecodeEnergy  common.RangeValue
+	ADPe          ADPe
+	GWP           GWP
+	PE            PE
+	// CatalogVersion identifies the model catalog that produced the estimate.
+	CatalogVersion aimodel.CatalogVersion
+	// Warnings lists the warnings of the model and those raised while estimating, eg when the electricity mix of
+	// the request region is unknown and the mix of its country was used instead.
+	Warnings []aimodel.Warning
 }
 
+// WarningElectricityMixFallback is the code of the warning raised when the electricity mix of a parent geo is
+// used because the requested region is not in the dataset.
+const WarningElectricityMixFallback = "electricity-mix-fallback"
+
+// WarningGPUCoefficientsPlaceholder is the code of the warning raised when an estimate relies on GPU coefficients
+// that have not been fitted to measurements, ie for embedding, image generation, transcription and speech
+// synthesis, and for the prefill of chat requests.
+const WarningGPUCoefficientsPlaceholder = "gpu-coefficients-placeholder"
+
+// WarningCarbonIntensityUnavailable is the code of the warning raised when the live carbon intensity source of an
+// ElectricityMixProvider failed and the average electricity mix was used instead.
+const WarningCarbonIntensityUnavailable = "carbon-intensity-unavailable"
+
+// WarningMarginalCarbonIntensity is the code of the warning raised when the GWP factor of the electricity mix is a
+// marginal carbon intensity, eg from WattTime, rather than the average intensity of the mix.
+const WarningMarginalCarbonIntensity = "marginal-carbon-intensity"
+
+// WarningElectricityFactorsFallback is the code of the warning raised when the GWP factor of the electricity mix
+// is the live carbon intensity of the request geo, but its ADPe and PE factors are those of another geo.
+const WarningElectricityFactorsFallback = "electricity-factors-fallback"
+
+// ErrUnsupportedTask is returned when a model is used for a request its task cannot serve, eg computing
+// token generation impacts for an embedding model.
+var ErrUnsupportedTask = errors.New("unsupported task")
+
 // ComputeImpacts computes the environmental and energy impact of the generative AI model.
+// It models the prefill of uncached input tokens followed by token generation, and returns an error wrapping
+// ErrUnsupportedTask for models that are not chat models.
 func ComputeImpacts(aiModel *aimodel.AIModel, server *gpuserver.GPUServer, req request.Request) (Impacts, error) {
+	return ComputeImpactsWithMixProvider(context.Background(), nil, aiModel, server, req)
+}
+
+// ComputeImpactsWithMixProvider computes the impacts of a chat request like ComputeImpacts, with the electricity
+// mix that mixes returns for the request geo and timestamp, eg the hourly carbon intensity at the time of the
+// request. A nil provider uses the average mixes of the default database.
+func ComputeImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	req request.Request,
+) (Impacts, error) {
+	if err := checkTask(aiModel, aimodel.TaskChat); err != nil {
+		return Impacts{}, err
+	}
 	modelRequiredMemory := aiModel.ModelRequiredMemory()
-	electricityMix := req.GetElectricityMix()
+	geo, err := req.ResolveGeo()
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get electricity mix: %w", err)
+	}
+	electricityMix, err := electricityMixAt(ctx, mixes, aiModel, geo, req.Timestamp)
+	if err != nil {
+		return Impacts{}, err
+	}
 
 	gpuRequiredCount, err := server.GPURequiredCount(modelRequiredMemory)
 	if err != nil {
 		return Impacts{}, fmt.Errorf("failed to get GPU required count: %w", err)
 	}
 
+	prefillTokenCount, err := req.PrefillTokenCount()
+	if err != nil {
+		return Impacts{}, fmt.Errorf("invalid request: %w", err)
+	}
+
 	paramsActiveMax := aiModel.Architecture().Parameters.Active.Max
-	generationLatency, err := server.GenerationLatency(paramsActiveMax, req.OutputTokenCount, req.Latency)
+	var generationLatency common.RangeValue
+	if req.Streamed() {
+		generationLatency, err = server.StreamingLatency(paramsActiveMax, prefillTokenCount, req.OutputTokenCount,
+			req.Latency.Seconds(), gpuserver.StreamTiming{
+				TimeToFirstTokenSecs:  req.TimeToFirstToken.Seconds(),
+				InterTokenLatencySecs: req.InterTokenLatency.Seconds(),
+			})
+	} else {
+		generationLatency, err = server.InferenceLatency(paramsActiveMax, prefillTokenCount, req.OutputTokenCount,
+			req.Latency.Seconds())
+	}
 	if err != nil {
 		return Impacts{}, fmt.Errorf("failed to get generation latency: %w", err)
 	}
 
-	gpuEnergyKWH, err := server.GPUEnergyKWH(paramsActiveMax, req.OutputTokenCount)
+	decodeEnergyKWH, err := server.GPUEnergyKWH(paramsActiveMax, req.OutputTokenCount)
 	if err != nil {
 		return Impacts{}, fmt.Errorf("failed to get GPU energy: %w", err)
 	}
+	var prefillEnergyKWH common.RangeValue
+	if prefillTokenCount > 0 {
+		prefillEnergyKWH, err = server.PrefillEnergyKWH(paramsActiveMax, prefillTokenCount)
+		if err != nil {
+			return Impacts{}, fmt.Errorf("failed to get GPU prefill energy: %w", err)
+		}
+	}
+	gpuEnergyKWH := common.RangeValue{
+		Min: prefillEnergyKWH.Min + decodeEnergyKWH.Min,
+		Max: prefillEnergyKWH.Max + decodeEnergyKWH.Max,
+	}
+
+	impacts, err := requestImpacts(aiModel, server, gpuRequiredCount, gpuEnergyKWH, generationLatency, electricityMix)
+	if err != nil {
+		return Impacts{}, err
+	}
+	impacts.PrefillEnergy = gpuRequestEnergy(server, gpuRequiredCount, prefillEnergyKWH)
+	impacts.DecodeEnergy = gpuRequestEnergy(server, gpuRequiredCount, decodeEnergyKWH)
+	if prefillTokenCount > 0 {
+		impacts.Warnings = append(impacts.Warnings, placeholderCoefficientsWarning("prompt prefill"))
+	}
+	return impacts, nil
+}
 
-	serverEnergyKWH, err := server.ServerEnergyBaseline(generationLatency.Max, gpuRequiredCount)
+// gpuRequestEnergy returns the energy in kWh drawn by gpuRequiredCount GPUs that each consume gpuEnergyKWH,
+// including datacenter overhead.
+func gpuRequestEnergy(
+	server *gpuserver.GPUServer,
+	gpuRequiredCount int,
+	gpuEnergyKWH common.RangeValue,
+) common.RangeValue {
+	return common.RangeValue{
+		Min: server.DatacenterPUE * float64(gpuRequiredCount) * gpuEnergyKWH.Min,
+		Max: server.DatacenterPUE * float64(gpuRequiredCount) * gpuEnergyKWH.Max,
+	}
+}
+
+// requestImpacts computes the impacts of a request that keeps gpuRequiredCount GPUs of server busy for
+// latency seconds, each of them consuming gpuEnergyKWH.
+func requestImpacts(
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	gpuRequiredCount int,
+	gpuEnergyKWH common.RangeValue,
+	latency common.RangeValue,
+	electricityMix request.ElectricityMix,
+) (Impacts, error) {
+	serverEnergyKWH, err := server.ServerEnergyBaseline(latency.Max, gpuRequiredCount)
 	if err != nil {
 		return Impacts{}, fmt.Errorf("failed to get server energy: %w", err)
 	}
@@ -79,29 +204,149 @@ func ComputeImpacts(aiModel *aimodel.AIModel, server *gpuserver.GPUServer, req r
 	adpeImpact := &ADPe{}
 	adpeImpact.CalculateRequestUsage(requestEnergy, electricityMix.ADPe)
 	adpeImpact.CalculateServerGPUEmbodied(server, gpuRequiredCount)
-	adpeImpact.CalculateRequestEmbodied(float64(server.HardwareLifespan), generationLatency)
+	adpeImpact.CalculateRequestEmbodied(float64(server.HardwareLifespan), latency)
 	adpeImpact.CalculateTotal()
 
 	gwpImpact := &GWP{}
 	gwpImpact.CalculateRequestUsage(requestEnergy, electricityMix.GWP)
 	gwpImpact.CalculateServerGPUEmbodied(server, gpuRequiredCount)
-	gwpImpact.CalculateRequestEmbodied(float64(server.HardwareLifespan), generationLatency)
+	gwpImpact.CalculateRequestEmbodied(float64(server.HardwareLifespan), latency)
 	gwpImpact.CalculateTotal()
 
 	peImpact := &PE{}
 	peImpact.CalculateRequestUsage(requestEnergy, electricityMix.PE)
 	peImpact.CalculateServerGPUEmbodied(server, gpuRequiredCount)
-	peImpact.CalculateRequestEmbodied(float64(server.HardwareLifespan), generationLatency)
+	peImpact.CalculateRequestEmbodied(float64(server.HardwareLifespan), latency)
 	peImpact.CalculateTotal()
 
 	return Impacts{
-		Energy: requestEnergy,
-		ADPe:   *adpeImpact,
-		GWP:    *gwpImpact,
-		PE:     *peImpact,
+		Energy:         requestEnergy,
+		ADPe:           *adpeImpact,
+		GWP:            *gwpImpact,
+		PE:             *peImpact,
+		CatalogVersion: aiModel.CatalogVersion(),
+		Warnings:       impactWarnings(aiModel, electricityMix),
 	}, nil
 }
 
+// placeholderImpacts computes the impacts of a non-chat request made at location that keeps the GPUs holding
+// aiModel busy for latency seconds, each of them consuming gpuEnergyKWH, and warns that the GPU coefficients for
+// workload are placeholders.
+func placeholderImpacts(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	location request.Location,
+	latency common.RangeValue,
+	gpuEnergyKWH common.RangeValue,
+	workload string,
+) (Impacts, error) {
+	geo, err := location.ResolveGeo()
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get electricity mix: %w", err)
+	}
+	electricityMix, err := electricityMixAt(ctx, mixes, aiModel, geo, location.Timestamp)
+	if err != nil {
+		return Impacts{}, err
+	}
+
+	gpuRequiredCount, err := server.GPURequiredCount(aiModel.ModelRequiredMemory())
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get GPU required count: %w", err)
+	}
+
+	impacts, err := requestImpacts(aiModel, server, gpuRequiredCount, gpuEnergyKWH, latency, electricityMix)
+	if err != nil {
+		return Impacts{}, err
+	}
+	impacts.Warnings = append(impacts.Warnings, placeholderCoefficientsWarning(workload))
+	return impacts, nil
+}
+
+// electricityMixAt returns the electricity mix of geo at a point in time from mixes, or from the default
+// database when mixes is nil. Requests without a geo use the mix of the geo where the provider of aiModel
+// serves requests, or the world average for providers without a default geo.
+func electricityMixAt(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel aimodel.AIModelIface,
+	geo string,
+	at time.Time,
+) (request.ElectricityMix, error) {
+	if mixes == nil {
+		database, err := request.DefaultMixDatabase()
+		if err != nil {
+			return request.ElectricityMix{}, fmt.Errorf("failed to get electricity mix: %w", err)
+		}
+		mixes = database
+	}
+	if geo == "" {
+		geo, _ = request.ProviderGeo(string(aiModel.Provider()))
+	}
+	electricityMix, err := mixes.ElectricityMix(ctx, geo, at)
+	if err != nil {
+		return request.ElectricityMix{}, fmt.Errorf("failed to get electricity mix: %w", err)
+	}
+	return electricityMix, nil
+}
+
+// impactWarnings returns the warnings of aiModel followed by the warnings about how electricityMix was obtained.
+func impactWarnings(aiModel *aimodel.AIModel, electricityMix request.ElectricityMix) []aimodel.Warning {
+	warnings := slices.Clone(aiModel.Warnings())
+	if electricityMix.Fallback() {
+		warnings = append(warnings, aimodel.Warning{
+			Code: WarningElectricityMixFallback,
+			Message: fmt.Sprintf("No electricity mix is known for %q, the mix of %q was used instead.",
+				electricityMix.RequestedGeo, electricityMix.Geo),
+			Severity: aimodel.SeverityLowConfidence,
+		})
+	}
+	if electricityMix.FactorsGeo != "" {
+		warnings = append(warnings, aimodel.Warning{
+			Code: WarningElectricityFactorsFallback,
+			Message: fmt.Sprintf("The ADPe and PE factors of %q are unknown, those of %q were used with the live "+
+				"carbon intensity of %q.", electricityMix.Geo, electricityMix.FactorsGeo, electricityMix.Geo),
+			Severity: aimodel.SeverityLowConfidence,
+		})
+	}
+	if electricityMix.SourceError != "" {
+		warnings = append(warnings, aimodel.Warning{
+			Code: WarningCarbonIntensityUnavailable,
+			Message: fmt.Sprintf("The live carbon intensity is unavailable (%s), the average electricity mix of %q "+
+				"was used instead.", electricityMix.SourceError, electricityMix.Geo),
+			Severity: aimodel.SeverityLowConfidence,
+		})
+	}
+	if electricityMix.Marginal {
+		warnings = append(warnings, aimodel.Warning{
+			Code: WarningMarginalCarbonIntensity,
+			Message: "The GWP of the electricity mix is a marginal carbon intensity, the intensity of the plants " +
+				"that respond to a change in demand, not the average intensity of the mix.",
+			Severity: aimodel.SeverityInfo,
+		})
+	}
+	return warnings
+}
+
+// placeholderCoefficientsWarning returns the warning raised when the estimate of workload relies on placeholder
+// GPU coefficients.
+func placeholderCoefficientsWarning(workload string) aimodel.Warning {
+	return aimodel.Warning{
+		Code: WarningGPUCoefficientsPlaceholder,
+		Message: fmt.Sprintf("The GPU coefficients for %s are placeholders that have not been fitted to "+
+			"measurements, expect lower precision.", workload),
+		Severity: aimodel.SeverityLowConfidence,
+	}
+}
+
+func checkTask(aiModel aimodel.AIModelIface, task aimodel.Task) error {
+	if aiModel.Task() != task {
+		return fmt.Errorf("%w: model %q serves %s requests, not %s", ErrUnsupportedTask, aiModel.Name(), aiModel.Task(), task)
+	}
+	return nil
+}
+
 func requestUsage(requestEnergy common.RangeValue, electricityMix float64) common.RangeValue {
 	return common.RangeValue{
 		Min: requestEnergy.Min * electricityMix,
@@ -0,0 +1,646 @@
+package impact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/omegabytes/ecologits-go/gpuserver"
+	"github.com/omegabytes/ecologits-go/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeImpacts_Task(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	req := request.Request{OutputTokenCount: 100, Latency: 5 * time.Second, Geo: "USA"}
+	dense := aimodel.Architecture{
+		Type:       aimodel.DENSE,
+		Parameters: aimodel.Parameters{Total: common.RangeValue{Min: 7, Max: 7}},
+	}
+
+	tests := []struct {
+		name    string
+		task    aimodel.Task
+		wantErr bool
+	}{
+		{
+			name: "should compute impacts of chat models",
+			task: aimodel.TaskChat,
+		},
+		{
+			name: "should compute impacts of models without a task",
+		},
+		{
+			name:    "should refuse embedding models",
+			task:    aimodel.TaskEmbeddings,
+			wantErr: true,
+		},
+		{
+			name:    "should refuse speech to text models",
+			task:    aimodel.TaskSpeechToText,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := aimodel.NewCustomAIModel(aimodel.ModelSpec{
+				Name:         "custom",
+				Provider:     aimodel.OpenAI,
+				Architecture: dense,
+				Task:         tt.task,
+			})
+			require.NoError(t, err)
+
+			got, err := ComputeImpacts(model, server, req)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrUnsupportedTask))
+				assert.Equal(t, Impacts{}, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Greater(t, got.Energy.Max, 0.0)
+		})
+	}
+}
+
+func TestComputeImpacts_ElectricityMix(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	model, err := aimodel.NewAIModel("gpt-4o-mini")
+	require.NoError(t, err)
+
+	compute := func(geo string) (Impacts, error) {
+		return ComputeImpacts(model, server, request.Request{OutputTokenCount: 100, Latency: 5 * time.Second, Geo: geo})
+	}
+	usa, err := compute("USA")
+	require.NoError(t, err)
+	france, err := compute("FR")
+	require.NoError(t, err)
+
+	assert.Equal(t, usa.Energy, france.Energy)
+	assert.InEpsilon(t, 0.0812/0.67978, france.GWP.RequestImpact.Max/usa.GWP.RequestImpact.Max, 1e-9)
+
+	assert.Equal(t, model.Warnings(), france.Warnings)
+
+	caiso, err := ComputeImpactsWithMixProvider(context.Background(),
+		request.NewMixDatabase([]request.ElectricityMix{{Geo: "US-CAISO", ADPe: 6e-08, GWP: 0.23, PE: 10}}),
+		model, server, request.Request{OutputTokenCount: 100, Latency: 5 * time.Second, Geo: "US-CAISO"})
+	require.NoError(t, err)
+	assert.Less(t, caiso.GWP.RequestImpact.Max, usa.GWP.RequestImpact.Max)
+	assert.Equal(t, model.Warnings(), caiso.Warnings)
+
+	paris, err := compute("FR-IDF")
+	require.NoError(t, err)
+	assert.Equal(t, france.GWP, paris.GWP)
+	assert.Equal(t, slices.Concat(model.Warnings(), []aimodel.Warning{{
+		Code:     WarningElectricityMixFallback,
+		Message:  `No electricity mix is known for "FR-IDF", the mix of "FRA" was used instead.`,
+		Severity: aimodel.SeverityLowConfidence,
+	}}), paris.Warnings)
+
+	_, err = compute("Atlantis")
+	assert.ErrorIs(t, err, request.ErrUnknownGeo)
+}
+
+func TestComputeImpacts_RegionFallback(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	model, err := aimodel.NewAIModel("gpt-4o-mini")
+	require.NoError(t, err)
+	fallbackWarning := func(region, country string) []aimodel.Warning {
+		return slices.Concat(model.Warnings(), []aimodel.Warning{{
+			Code: WarningElectricityMixFallback,
+			Message: fmt.Sprintf("No electricity mix is known for %q, the mix of %q was used instead.",
+				region, country),
+			Severity: aimodel.SeverityLowConfidence,
+		}})
+	}
+
+	t.Run("should fall back from a missing subregion of the embedded dataset", func(t *testing.T) {
+		req, err := request.New(request.WithOutputTokens(100), request.WithLatency(5*time.Second),
+			request.WithGeo("US-CAISO"))
+		require.NoError(t, err)
+		caiso, err := ComputeImpacts(model, server, req)
+		require.NoError(t, err)
+		usa, err := ComputeImpacts(model, server,
+			request.Request{OutputTokenCount: 100, Latency: 5 * time.Second, Geo: "US"})
+		require.NoError(t, err)
+
+		assert.Equal(t, usa.GWP, caiso.GWP)
+		assert.Equal(t, fallbackWarning("US-CAISO", "USA"), caiso.Warnings)
+	})
+
+	t.Run("should use loaded subregions and fall back from the others", func(t *testing.T) {
+		mixes, err := request.ParseElectricityMixes(strings.NewReader(
+			"geo,adpe,gwp,pe\nCAN,6e-08,0.13,10\nCA-QC,4e-08,0.002,9.8\n"))
+		require.NoError(t, err)
+		database := request.NewMixDatabase(mixes)
+		compute := func(geo string) Impacts {
+			got, err := ComputeImpactsWithMixProvider(context.Background(), database, model, server,
+				request.Request{OutputTokenCount: 100, Latency: 5 * time.Second, Geo: geo})
+			require.NoError(t, err)
+			return got
+		}
+		quebec := compute("CA-QC")
+		alberta := compute("CA-AB")
+
+		assert.Less(t, quebec.GWP.RequestImpact.Max, alberta.GWP.RequestImpact.Max)
+		assert.Equal(t, model.Warnings(), quebec.Warnings)
+		assert.Equal(t, fallbackWarning("CA-AB", "CAN"), alberta.Warnings)
+	})
+}
+
+func TestComputeImpacts_DefaultGeo(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	dense := aimodel.Architecture{
+		Type:       aimodel.DENSE,
+		Parameters: aimodel.Parameters{Total: common.RangeValue{Min: 7, Max: 7}},
+	}
+
+	tests := []struct {
+		name     string
+		provider aimodel.Provider
+		req      request.Request
+		wantGeo  string
+	}{
+		{
+			name:     "should use the geo of the provider region",
+			provider: aimodel.OpenAI,
+			req:      request.Request{ProviderRegion: "europe-west9"},
+			wantGeo:  "FR",
+		},
+		{
+			name:     "should use the default geo of the provider",
+			provider: aimodel.OpenAI,
+			wantGeo:  "USA",
+		},
+		{
+			name:     "should use the world average for providers without a default geo",
+			provider: aimodel.HuggingfaceHub,
+			wantGeo:  request.WorldGeo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := aimodel.NewCustomAIModel(aimodel.ModelSpec{
+				Name:         "custom",
+				Provider:     tt.provider,
+				Architecture: dense,
+			})
+			require.NoError(t, err)
+			tt.req.OutputTokenCount = 100
+			tt.req.Latency = 5 * time.Second
+
+			got, err := ComputeImpacts(model, server, tt.req)
+			require.NoError(t, err)
+			want, err := ComputeImpacts(model, server, request.Request{
+				OutputTokenCount: 100,
+				Latency:          5 * time.Second,
+				Geo:              tt.wantGeo,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestComputeImpactsWithMixProvider(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	model, err := aimodel.NewAIModel("gpt-4o-mini")
+	require.NoError(t, err)
+	fallback, err := request.DefaultMixDatabase()
+	require.NoError(t, err)
+	mixes, err := request.ParseHourlyMixes(strings.NewReader(
+		"geo,datetime,gwp\nFR,2025-01-15T03:00:00Z,0.02\nFR,2025-01-15T18:00:00Z,0.08\n"), fallback)
+	require.NoError(t, err)
+
+	compute := func(at time.Time) Impacts {
+		got, err := ComputeImpactsWithMixProvider(context.Background(), mixes, model, server,
+			request.Request{OutputTokenCount: 100, Latency: 5 * time.Second, Geo: "FR", Timestamp: at})
+		require.NoError(t, err)
+		return got
+	}
+	night := compute(time.Date(2025, 1, 15, 3, 30, 0, 0, time.UTC))
+	evening := compute(time.Date(2025, 1, 15, 18, 30, 0, 0, time.UTC))
+	undated := compute(time.Time{})
+
+	assert.Equal(t, night.Energy, evening.Energy)
+	assert.InEpsilon(t, 4, evening.GWP.RequestImpact.Max/night.GWP.RequestImpact.Max, 1e-9)
+	assert.Equal(t, night.ADPe, evening.ADPe)
+
+	average, err := ComputeImpacts(model, server,
+		request.Request{OutputTokenCount: 100, Latency: 5 * time.Second, Geo: "FR"})
+	require.NoError(t, err)
+	assert.Equal(t, average, undated)
+}
+
+// staticMixProvider is an ElectricityMixProvider that returns the same mix for every geo and time.
+type staticMixProvider request.ElectricityMix
+
+func (m staticMixProvider) ElectricityMix(context.Context, string, time.Time) (request.ElectricityMix, error) {
+	return request.ElectricityMix(m), nil
+}
+
+func TestComputeImpactsWithMixProvider_Warnings(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	model, err := aimodel.NewAIModel("gpt-4o-mini")
+	require.NoError(t, err)
+	france := request.ElectricityMix{Geo: "FRA", RequestedGeo: "FRA", ADPe: 4.858e-08, GWP: 0.0812, PE: 11.289}
+
+	tests := []struct {
+		name     string
+		mix      func(request.ElectricityMix) request.ElectricityMix
+		expected []aimodel.Warning
+	}{
+		{
+			name: "should not warn about a live average intensity",
+			mix:  func(m request.ElectricityMix) request.ElectricityMix { return m },
+		},
+		{
+			name: "should warn when the live source failed",
+			mix: func(m request.ElectricityMix) request.ElectricityMix {
+				m.SourceError = "rate limited"
+				return m
+			},
+			expected: []aimodel.Warning{{
+				Code: WarningCarbonIntensityUnavailable,
+				Message: `The live carbon intensity is unavailable (rate limited), the average electricity mix of ` +
+					`"FRA" was used instead.`,
+				Severity: aimodel.SeverityLowConfidence,
+			}},
+		},
+		{
+			name: "should warn about the factors of another geo without a mix fallback warning",
+			mix: func(m request.ElectricityMix) request.ElectricityMix {
+				m.Geo, m.RequestedGeo, m.FactorsGeo = "FR-IDF", "FR-IDF", "FRA"
+				return m
+			},
+			expected: []aimodel.Warning{{
+				Code: WarningElectricityFactorsFallback,
+				Message: `The ADPe and PE factors of "FR-IDF" are unknown, those of "FRA" were used with the live ` +
+					`carbon intensity of "FR-IDF".`,
+				Severity: aimodel.SeverityLowConfidence,
+			}},
+		},
+		{
+			name: "should flag marginal intensities",
+			mix: func(m request.ElectricityMix) request.ElectricityMix {
+				m.Marginal = true
+				return m
+			},
+			expected: []aimodel.Warning{{
+				Code: WarningMarginalCarbonIntensity,
+				Message: "The GWP of the electricity mix is a marginal carbon intensity, the intensity of the plants " +
+					"that respond to a change in demand, not the average intensity of the mix.",
+				Severity: aimodel.SeverityInfo,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComputeImpactsWithMixProvider(context.Background(), staticMixProvider(tt.mix(france)), model,
+				server, request.Request{OutputTokenCount: 100, Latency: 5 * time.Second, Geo: "FR"})
+			require.NoError(t, err)
+			assert.Equal(t, slices.Concat(model.Warnings(), tt.expected), got.Warnings)
+		})
+	}
+}
+
+func TestComputeImpactsWithMixProvider_NonChat(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	fallback, err := request.DefaultMixDatabase()
+	require.NoError(t, err)
+	mixes, err := request.ParseHourlyMixes(strings.NewReader(
+		"geo,datetime,gwp\nFR,2025-01-15T03:00:00Z,0.02\nFR,2025-01-15T18:00:00Z,0.08\n"), fallback)
+	require.NoError(t, err)
+	ctx := context.Background()
+	load := func(t *testing.T, name string) *aimodel.AIModel {
+		t.Helper()
+		model, err := aimodel.NewAIModel(name)
+		require.NoError(t, err)
+		return model
+	}
+	embedding := load(t, "text-embedding-3-small")
+	sdxl := load(t, "stabilityai/stable-diffusion-xl-base-1.0")
+	whisper := load(t, "whisper-1")
+	tts := load(t, "tts-1")
+
+	tests := []struct {
+		name    string
+		compute func(at time.Time) (Impacts, error)
+	}{
+		{
+			name: "embedding",
+			compute: func(at time.Time) (Impacts, error) {
+				return ComputeEmbeddingImpactsWithMixProvider(ctx, mixes, embedding, server, request.EmbeddingRequest{
+					InputTokenCount: 1000, BatchSize: 1, Latency: time.Second,
+					Location: request.Location{Geo: "FR", Timestamp: at},
+				})
+			},
+		},
+		{
+			name: "image generation",
+			compute: func(at time.Time) (Impacts, error) {
+				return ComputeImageImpactsWithMixProvider(ctx, mixes, sdxl, server, request.ImageRequest{
+					ImageCount: 1, Width: 1024, Height: 1024, Latency: 10 * time.Second,
+					Location: request.Location{Geo: "FR", Timestamp: at},
+				})
+			},
+		},
+		{
+			name: "transcription",
+			compute: func(at time.Time) (Impacts, error) {
+				return ComputeTranscriptionImpactsWithMixProvider(ctx, mixes, whisper, server, request.TranscriptionRequest{
+					AudioDuration: time.Minute, Latency: 5 * time.Second,
+					Location: request.Location{Geo: "FR", Timestamp: at},
+				})
+			},
+		},
+		{
+			name: "speech synthesis",
+			compute: func(at time.Time) (Impacts, error) {
+				return ComputeSpeechImpactsWithMixProvider(ctx, mixes, tts, server, request.SpeechRequest{
+					CharacterCount: 500, Latency: 5 * time.Second,
+					Location: request.Location{Geo: "FR", Timestamp: at},
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			night, err := tt.compute(time.Date(2025, 1, 15, 3, 30, 0, 0, time.UTC))
+			require.NoError(t, err)
+			evening, err := tt.compute(time.Date(2025, 1, 15, 18, 30, 0, 0, time.UTC))
+			require.NoError(t, err)
+			assert.Equal(t, night.Energy, evening.Energy)
+			assert.InEpsilon(t, 4, evening.GWP.RequestImpact.Max/night.GWP.RequestImpact.Max, 1e-9)
+		})
+	}
+}
+
+func TestComputeEmbeddingImpacts(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	embedding, err := aimodel.NewAIModel("text-embedding-3-small")
+	require.NoError(t, err)
+	chat, err := aimodel.NewAIModel("gpt-4o-mini")
+	require.NoError(t, err)
+	usa := request.Location{Geo: "USA"}
+
+	t.Run("should compute impacts of embedding calls", func(t *testing.T) {
+		got, err := ComputeEmbeddingImpacts(embedding, server,
+			request.EmbeddingRequest{InputTokenCount: 10000, BatchSize: 32, Latency: time.Second, Location: usa})
+		assert.NoError(t, err)
+		assert.Greater(t, got.Energy.Min, 0.0)
+		assert.Greater(t, got.GWP.TotalImpact.Max, got.GWP.RequestImpact.Max)
+		assert.Equal(t, embedding.CatalogVersion(), got.CatalogVersion)
+		assert.Equal(t, slices.Concat(embedding.Warnings(), []aimodel.Warning{placeholderCoefficientsWarning("embedding")}),
+			got.Warnings)
+
+		larger, err := ComputeEmbeddingImpacts(embedding, server,
+			request.EmbeddingRequest{InputTokenCount: 100000, BatchSize: 32, Latency: 10 * time.Second, Location: usa})
+		assert.NoError(t, err)
+		assert.Greater(t, larger.Energy.Max, got.Energy.Max)
+	})
+
+	t.Run("should locate requests by provider region", func(t *testing.T) {
+		byRegion, err := ComputeEmbeddingImpacts(embedding, server, request.EmbeddingRequest{
+			InputTokenCount: 100, BatchSize: 1, Latency: time.Second,
+			Location: request.Location{ProviderRegion: "eu-west-3", CloudProvider: request.AWS},
+		})
+		assert.NoError(t, err)
+		byGeo, err := ComputeEmbeddingImpacts(embedding, server,
+			request.EmbeddingRequest{
+				InputTokenCount: 100, BatchSize: 1, Latency: time.Second, Location: request.Location{Geo: "FR"},
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, byGeo, byRegion)
+
+		_, err = ComputeEmbeddingImpacts(embedding, server, request.EmbeddingRequest{
+			InputTokenCount: 100, BatchSize: 1, Latency: time.Second,
+			Location: request.Location{ProviderRegion: "moon-south-1"},
+		})
+		assert.ErrorIs(t, err, request.ErrUnknownRegion)
+	})
+
+	t.Run("should refuse chat models", func(t *testing.T) {
+		_, err := ComputeEmbeddingImpacts(chat, server,
+			request.EmbeddingRequest{InputTokenCount: 100, BatchSize: 1, Latency: time.Second, Location: usa})
+		assert.True(t, errors.Is(err, ErrUnsupportedTask))
+	})
+
+	t.Run("should return error when batch size is missing", func(t *testing.T) {
+		_, err := ComputeEmbeddingImpacts(embedding, server,
+			request.EmbeddingRequest{InputTokenCount: 100, Latency: time.Second, Location: usa})
+		assert.EqualError(t, err, "failed to get embedding latency: batchSize must be greater than 0")
+	})
+}
+
+func TestComputeImageImpacts(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	sdxl, err := aimodel.NewAIModel("stabilityai/stable-diffusion-xl-base-1.0")
+	require.NoError(t, err)
+	usa := request.Location{Geo: "USA"}
+
+	t.Run("should compute impacts of image generation calls", func(t *testing.T) {
+		got, err := ComputeImageImpacts(sdxl, server,
+			request.ImageRequest{ImageCount: 1, Width: 1024, Height: 1024, Steps: 30, Latency: 10 * time.Second, Location: usa})
+		assert.NoError(t, err)
+		assert.Greater(t, got.Energy.Min, 0.0)
+		assert.Greater(t, got.GWP.RequestImpact.Min, 0.0)
+		assert.Greater(t, got.GWP.EmbodiedImpact.Min, 0.0)
+		assert.Equal(t, []aimodel.Warning{placeholderCoefficientsWarning("image generation")}, got.Warnings)
+
+		larger, err := ComputeImageImpacts(sdxl, server,
+			request.ImageRequest{ImageCount: 4, Width: 1024, Height: 1024, Steps: 30, Latency: 60 * time.Second, Location: usa})
+		assert.NoError(t, err)
+		assert.Greater(t, larger.Energy.Max, got.Energy.Max)
+	})
+
+	t.Run("should default the number of diffusion steps", func(t *testing.T) {
+		defaulted, err := ComputeImageImpacts(sdxl, server,
+			request.ImageRequest{ImageCount: 1, Width: 512, Height: 512, Latency: 60 * time.Second, Location: usa})
+		assert.NoError(t, err)
+		explicit, err := ComputeImageImpacts(sdxl, server,
+			request.ImageRequest{ImageCount: 1, Width: 512, Height: 512, Steps: request.DefaultImageSteps,
+				Latency: 60 * time.Second, Location: usa})
+		assert.NoError(t, err)
+		assert.Equal(t, explicit, defaulted)
+	})
+
+	t.Run("should return error when the resolution is missing", func(t *testing.T) {
+		_, err := ComputeImageImpacts(sdxl, server,
+			request.ImageRequest{ImageCount: 1, Latency: 10 * time.Second, Location: usa})
+		assert.EqualError(t, err, "failed to get image generation latency: megapixels must be greater than 0")
+	})
+
+	t.Run("should refuse models that do not generate images", func(t *testing.T) {
+		chat, err := aimodel.NewAIModel("gpt-4o-mini")
+		require.NoError(t, err)
+		_, err = ComputeImageImpacts(chat, server,
+			request.ImageRequest{ImageCount: 1, Width: 1024, Height: 1024, Latency: 10 * time.Second, Location: usa})
+		assert.True(t, errors.Is(err, ErrUnsupportedTask))
+	})
+}
+
+func TestComputeSpeechImpacts(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	whisper, err := aimodel.NewAIModel("whisper-1")
+	require.NoError(t, err)
+	tts, err := aimodel.NewAIModel("tts-1")
+	require.NoError(t, err)
+	usa := request.Location{Geo: "USA"}
+
+	t.Run("should compute impacts of transcriptions from audio duration", func(t *testing.T) {
+		minute, err := ComputeTranscriptionImpacts(whisper, server,
+			request.TranscriptionRequest{AudioDuration: 60 * time.Second, Latency: 5 * time.Second, Location: usa})
+		assert.NoError(t, err)
+		assert.Greater(t, minute.Energy.Min, 0.0)
+		assert.Equal(t, []aimodel.Warning{placeholderCoefficientsWarning("transcription")}, minute.Warnings)
+
+		hour, err := ComputeTranscriptionImpacts(whisper, server,
+			request.TranscriptionRequest{AudioDuration: 3600 * time.Second, Latency: 120 * time.Second, Location: usa})
+		assert.NoError(t, err)
+		assert.Greater(t, hour.Energy.Max, minute.Energy.Max)
+	})
+
+	t.Run("should compute impacts of speech synthesis from character count", func(t *testing.T) {
+		got, err := ComputeSpeechImpacts(tts, server,
+			request.SpeechRequest{CharacterCount: 500, Latency: 5 * time.Second, Location: usa})
+		assert.NoError(t, err)
+		assert.Greater(t, got.Energy.Min, 0.0)
+		assert.Greater(t, got.PE.TotalImpact.Max, 0.0)
+		assert.Equal(t, slices.Concat(tts.Warnings(), []aimodel.Warning{placeholderCoefficientsWarning("speech synthesis")}),
+			got.Warnings)
+	})
+
+	t.Run("should locate requests by provider region", func(t *testing.T) {
+		byRegion, err := ComputeSpeechImpacts(tts, server,
+			request.SpeechRequest{
+				CharacterCount: 500, Latency: 5 * time.Second, Location: request.Location{ProviderRegion: "francecentral"},
+			})
+		assert.NoError(t, err)
+		byGeo, err := ComputeSpeechImpacts(tts, server,
+			request.SpeechRequest{CharacterCount: 500, Latency: 5 * time.Second, Location: request.Location{Geo: "FR"}})
+		assert.NoError(t, err)
+		assert.Equal(t, byGeo, byRegion)
+	})
+
+	t.Run("should return error when audio duration is missing", func(t *testing.T) {
+		_, err := ComputeTranscriptionImpacts(whisper, server,
+			request.TranscriptionRequest{Latency: 5 * time.Second, Location: usa})
+		assert.EqualError(t, err, "failed to get transcription latency: audioSecs must be greater than 0")
+	})
+
+	t.Run("should refuse models of another task", func(t *testing.T) {
+		_, err := ComputeTranscriptionImpacts(tts, server,
+			request.TranscriptionRequest{AudioDuration: 60 * time.Second, Latency: 5 * time.Second, Location: usa})
+		assert.True(t, errors.Is(err, ErrUnsupportedTask))
+		_, err = ComputeSpeechImpacts(whisper, server,
+			request.SpeechRequest{CharacterCount: 500, Latency: 5 * time.Second, Location: usa})
+		assert.True(t, errors.Is(err, ErrUnsupportedTask))
+	})
+}
+
+func TestComputeImpacts_Prefill(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	model, err := aimodel.NewAIModel("gpt-4o-mini")
+	require.NoError(t, err)
+
+	compute := func(t *testing.T, req request.Request) Impacts {
+		t.Helper()
+		got, err := ComputeImpacts(model, server, req)
+		require.NoError(t, err)
+		return got
+	}
+
+	t.Run("should report no prefill energy without input tokens", func(t *testing.T) {
+		got := compute(t, request.Request{OutputTokenCount: 10, Latency: 30 * time.Second, Geo: "USA"})
+		assert.Equal(t, common.RangeValue{}, got.PrefillEnergy)
+		assert.Greater(t, got.DecodeEnergy.Min, 0.0)
+		assert.Greater(t, got.Energy.Min, got.DecodeEnergy.Min)
+		assert.Equal(t, model.Warnings(), got.Warnings)
+	})
+
+	t.Run("should account for long prompts with short answers", func(t *testing.T) {
+		short := compute(t, request.Request{OutputTokenCount: 10, Latency: 30 * time.Second, Geo: "USA"})
+		long := compute(t,
+			request.Request{InputTokenCount: 100000, OutputTokenCount: 10, Latency: 30 * time.Second, Geo: "USA"})
+		assert.Greater(t, long.PrefillEnergy.Min, long.DecodeEnergy.Max)
+		assert.Equal(t, short.DecodeEnergy, long.DecodeEnergy)
+		assert.Greater(t, long.Energy.Min, short.Energy.Max)
+		assert.Greater(t, long.GWP.EmbodiedImpact.Min, short.GWP.EmbodiedImpact.Max)
+		assert.Equal(t, slices.Concat(model.Warnings(), []aimodel.Warning{placeholderCoefficientsWarning("prompt prefill")}),
+			long.Warnings)
+	})
+
+	t.Run("should not prefill cached input tokens", func(t *testing.T) {
+		uncached := compute(t,
+			request.Request{InputTokenCount: 20000, OutputTokenCount: 10, Latency: 30 * time.Second, Geo: "USA"})
+		cached := compute(t, request.Request{
+			InputTokenCount: 100000, CachedInputTokenCount: 80000, OutputTokenCount: 10, Latency: 30 * time.Second, Geo: "USA",
+		})
+		assert.Equal(t, uncached, cached)
+	})
+
+	t.Run("should return error when cached tokens exceed input tokens", func(t *testing.T) {
+		_, err := ComputeImpacts(model, server, request.Request{
+			InputTokenCount: 10, CachedInputTokenCount: 20, OutputTokenCount: 10, Latency: 30 * time.Second, Geo: "USA",
+		})
+		assert.EqualError(t, err, "invalid request: cached input token count (20) exceeds input token count (10)")
+	})
+}
+
+func TestComputeImpacts_Streaming(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+	model, err := aimodel.NewAIModel("gpt-4o-mini")
+	require.NoError(t, err)
+
+	unary, err := ComputeImpacts(model, server,
+		request.Request{OutputTokenCount: 500, Latency: 60 * time.Second, Geo: "USA"})
+	require.NoError(t, err)
+
+	t.Run("should bound embodied impacts by the measured streaming latencies", func(t *testing.T) {
+		streamed, err := ComputeImpacts(model, server, request.Request{
+			OutputTokenCount: 500, Latency: 60 * time.Second,
+			TimeToFirstToken: 400 * time.Millisecond, InterTokenLatency: 10 * time.Millisecond, Geo: "USA",
+		})
+		assert.NoError(t, err)
+		assert.Less(t, streamed.GWP.EmbodiedImpact.Max, unary.GWP.EmbodiedImpact.Max)
+		assert.Less(t, streamed.Energy.Max, unary.Energy.Max)
+		assert.Equal(t, unary.DecodeEnergy, streamed.DecodeEnergy)
+	})
+
+	t.Run("should match unary calls when streaming is slower than the estimate", func(t *testing.T) {
+		streamed, err := ComputeImpacts(model, server, request.Request{
+			OutputTokenCount: 500, Latency: 60 * time.Second,
+			TimeToFirstToken: 5 * time.Second, InterTokenLatency: 100 * time.Millisecond, Geo: "USA",
+		})
+		assert.NoError(t, err)
+		assert.InDelta(t, unary.Energy.Max, streamed.Energy.Max, 1e-12)
+	})
+
+	t.Run("should return error when the time to first token exceeds the latency", func(t *testing.T) {
+		_, err := ComputeImpacts(model, server, request.Request{
+			OutputTokenCount: 500, Latency: time.Second, TimeToFirstToken: 2 * time.Second, Geo: "USA",
+		})
+		assert.EqualError(t, err,
+			"failed to get generation latency: timeToFirstTokenSecs cannot exceed requestLatencySecs")
+	})
+}
@@ -0,0 +1,86 @@
+package impact
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+	"github.com/omegabytes/ecologits-go/gpuserver"
+	"github.com/omegabytes/ecologits-go/request"
+)
+
+// ComputeTranscriptionImpacts computes the environmental and energy impact of a speech-to-text call from the
+// duration of the transcribed audio. It returns an error wrapping ErrUnsupportedTask for models that are not
+// speech-to-text models.
+func ComputeTranscriptionImpacts(
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	req request.TranscriptionRequest,
+) (Impacts, error) {
+	return ComputeTranscriptionImpactsWithMixProvider(context.Background(), nil, aiModel, server, req)
+}
+
+// ComputeTranscriptionImpactsWithMixProvider is ComputeTranscriptionImpacts priced at the electricity mix that
+// mixes returns for req, eg the hourly mix at the time the audio was transcribed rather than the yearly average.
+// Mixes that a provider could not fetch live carry a WarningCarbonIntensityUnavailable warning. A nil provider
+// uses the average mixes of the default database.
+func ComputeTranscriptionImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	req request.TranscriptionRequest,
+) (Impacts, error) {
+	if err := checkTask(aiModel, aimodel.TaskSpeechToText); err != nil {
+		return Impacts{}, err
+	}
+	paramsActiveMax := aiModel.Architecture().Parameters.Active.Max
+	transcriptionLatency, err := server.TranscriptionLatency(paramsActiveMax, req.AudioDuration.Seconds(),
+		req.Latency.Seconds())
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get transcription latency: %w", err)
+	}
+	gpuEnergyKWH, err := server.GPUBusyEnergyKWH(transcriptionLatency)
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get GPU energy: %w", err)
+	}
+	return placeholderImpacts(ctx, mixes, aiModel, server, req.Location, transcriptionLatency, gpuEnergyKWH,
+		"transcription")
+}
+
+// ComputeSpeechImpacts computes the environmental and energy impact of a text-to-speech call from the number
+// of synthesized characters. It returns an error wrapping ErrUnsupportedTask for models that are not
+// text-to-speech models.
+func ComputeSpeechImpacts(
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	req request.SpeechRequest,
+) (Impacts, error) {
+	return ComputeSpeechImpactsWithMixProvider(context.Background(), nil, aiModel, server, req)
+}
+
+// ComputeSpeechImpactsWithMixProvider is ComputeSpeechImpacts priced at the electricity mix that mixes returns
+// for req. Requests without a geo or provider region are priced at the mix of the default geo of the model
+// provider, see request.ProviderGeo. A nil provider uses the average mixes of the default database.
+func ComputeSpeechImpactsWithMixProvider(
+	ctx context.Context,
+	mixes request.ElectricityMixProvider,
+	aiModel *aimodel.AIModel,
+	server *gpuserver.GPUServer,
+	req request.SpeechRequest,
+) (Impacts, error) {
+	if err := checkTask(aiModel, aimodel.TaskTextToSpeech); err != nil {
+		return Impacts{}, err
+	}
+	paramsActiveMax := aiModel.Architecture().Parameters.Active.Max
+	speechLatency, err := server.SpeechLatency(paramsActiveMax, req.CharacterCount, req.Latency.Seconds())
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get speech latency: %w", err)
+	}
+	gpuEnergyKWH, err := server.GPUBusyEnergyKWH(speechLatency)
+	if err != nil {
+		return Impacts{}, fmt.Errorf("failed to get GPU energy: %w", err)
+	}
+	return placeholderImpacts(ctx, mixes, aiModel, server, req.Location, speechLatency, gpuEnergyKWH,
+		"speech synthesis")
+}
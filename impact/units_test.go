@@ -0,0 +1,119 @@
+package impact
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omegabytes/ecologits-go/aimodel"
+	"github.com/omegabytes/ecologits-go/common"
+	"github.com/omegabytes/ecologits-go/gpuserver"
+	"github.com/omegabytes/ecologits-go/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnitsAudit round-trips realistic requests, with latencies recorded by telemetry in milliseconds, and
+// checks the estimates against reference values computed by hand with the generic GPU server:
+// energies in kWh, GWP in kgCO2eq and latencies in seconds.
+func TestUnitsAudit(t *testing.T) {
+	server, err := gpuserver.GenericGPUServer()
+	require.NoError(t, err)
+
+	type reference struct {
+		energy      common.RangeValue
+		gwpUsage    common.RangeValue
+		gwpEmbodied common.RangeValue
+	}
+	tests := []struct {
+		name      string
+		model     string
+		latencyMs int64
+		compute   func(*aimodel.AIModel, time.Duration) (Impacts, error)
+		want      reference
+	}{
+		{
+			// 1 GPU (1.2 * 28B * 1 byte = 33.6 GB), generation takes 250 * 0.044756 s = 11.19 s which is
+			// clamped to the 4.2 s request. GPU energy: 250 * (8.91e-8 * 28 + 1.43e-6 ± 1.96 * 5.19e-7) kWh,
+			// server energy: 4.2 / 3600 * 1 kW / 100, energy: 1.2 * (server + GPU).
+			// Embodied GWP: 4.2 s / 5 years * (3000 / 100 + 143) kgCO2eq.
+			name:      "should clamp generation to a short chat request",
+			model:     "gpt-4o-mini",
+			latencyMs: 4200,
+			compute: func(model *aimodel.AIModel, latency time.Duration) (Impacts, error) {
+				return ComputeImpacts(model, server,
+					request.Request{OutputTokenCount: 250, Latency: latency, Geo: "USA"})
+			},
+			want: reference{
+				energy:      common.RangeValue{Min: 0.000886268, Max: 0.001496612},
+				gwpUsage:    common.RangeValue{Min: 0.00060246726104, Max: 0.00101736690536},
+				gwpEmbodied: common.RangeValue{Min: 4.6080669710806694e-06, Max: 4.6080669710806694e-06},
+			},
+		},
+		{
+			// Generation takes [11.18557, 11.19243] s, shorter than the 15 s request.
+			name:      "should use the estimated generation latency of a slow chat request",
+			model:     "gpt-4o-mini",
+			latencyMs: 15000,
+			compute: func(model *aimodel.AIModel, latency time.Duration) (Impacts, error) {
+				return ComputeImpacts(model, server,
+					request.Request{OutputTokenCount: 250, Latency: latency, Geo: "USA"})
+			},
+			want: reference{
+				energy:      common.RangeValue{Min: 0.0009095761, Max: 0.0015199201},
+				gwpUsage:    common.RangeValue{Min: 0.000618311641258, Max: 0.001033211285578},
+				gwpEmbodied: common.RangeValue{Min: 1.2272346588026384e-05, Max: 1.2279873097412484e-05},
+			},
+		},
+		{
+			// 2 GPUs (1.2 * 70.55B = 84.66 GB), prefill of 8000 tokens and generation of 400 tokens take
+			// [32.834184, 32.848296] s.
+			name:      "should add prefill to a long prompt on a self-hosted model",
+			model:     "meta-llama/Meta-Llama-3.1-70B-Instruct",
+			latencyMs: 45000,
+			compute: func(model *aimodel.AIModel, latency time.Duration) (Impacts, error) {
+				return ComputeImpacts(model, server,
+					request.Request{InputTokenCount: 8000, OutputTokenCount: 400, Latency: latency, Geo: "USA"})
+			},
+			want: reference{
+				energy:      common.RangeValue{Min: 0.00701295184, Max: 0.00897357904},
+				gwpUsage:    common.RangeValue{Min: 0.0047672644017952, Max: 0.0061000595598112},
+				gwpEmbodied: common.RangeValue{Min: 7.204862800608828e-05, Max: 7.207959421613394e-05},
+			},
+		},
+		{
+			// 90 s of audio take 90 * (8e-3 * 1.55 + 4e-3 ± 1.96e-3) = [1.2996, 1.6524] s of GPU time at 0.4 kW.
+			name:      "should convert audio duration to GPU time for transcriptions",
+			model:     "whisper-1",
+			latencyMs: 6000,
+			compute: func(model *aimodel.AIModel, latency time.Duration) (Impacts, error) {
+				return ComputeTranscriptionImpacts(model, server,
+					request.TranscriptionRequest{
+						AudioDuration: 90 * time.Second, Latency: latency, Location: request.Location{Geo: "USA"},
+					})
+			},
+			want: reference{
+				energy:      common.RangeValue{Min: 0.000178788, Max: 0.000225828},
+				gwpUsage:    common.RangeValue{Min: 0.00012153650664, Max: 0.00015351335784},
+				gwpEmbodied: common.RangeValue{Min: 1.4258675799086758e-06, Max: 1.8129452054794522e-06},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, err := aimodel.NewAIModel(tt.model)
+			require.NoError(t, err)
+
+			got, err := tt.compute(model, time.Duration(tt.latencyMs)*time.Millisecond)
+			require.NoError(t, err)
+			assertRange(t, tt.want.energy, got.Energy)
+			assertRange(t, tt.want.gwpUsage, got.GWP.RequestImpact)
+			assertRange(t, tt.want.gwpEmbodied, got.GWP.EmbodiedImpact)
+		})
+	}
+}
+
+func assertRange(t *testing.T, want, got common.RangeValue) {
+	t.Helper()
+	assert.InEpsilon(t, want.Min, got.Min, 1e-9)
+	assert.InEpsilon(t, want.Max, got.Max, 1e-9)
+}
@@ -0,0 +1,30 @@
+package request
+
+import "time"
+
+// EmbeddingRequest describes a call to an embedding model, which processes its input in a single forward pass
+// instead of generating tokens.
+type EmbeddingRequest struct {
+	// InputTokenCount is the total number of tokens across all inputs.
+	InputTokenCount float64
+	// BatchSize is the number of inputs embedded by the call.
+	BatchSize int
+	Latency   time.Duration
+	Location
+}
+
+// Validate checks every field of the request and returns ValidationErrors listing the invalid ones.
+func (r *EmbeddingRequest) Validate() error {
+	var errs ValidationErrors
+	if r.InputTokenCount <= 0 {
+		errs.add("InputTokenCount", r.InputTokenCount, "must be greater than 0")
+	}
+	if r.BatchSize <= 0 {
+		errs.add("BatchSize", r.BatchSize, "must be greater than 0")
+	}
+	if r.Latency <= 0 {
+		errs.add("Latency", r.Latency, "must be greater than 0")
+	}
+	r.validate(&errs)
+	return errs.err()
+}
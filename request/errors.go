@@ -0,0 +1,69 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidRequest is returned when a request fails validation.
+// Use errors.As with *ValidationError to get the invalid fields.
+var ErrInvalidRequest = errors.New("invalid request")
+
+// ValidationError describes an invalid request field.
+type ValidationError struct {
+	Field   string
+	Value   any
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s (got %v)", e.Field, e.Message, e.Value)
+}
+
+// Is reports whether target is ErrInvalidRequest.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// ValidationErrors aggregates every ValidationError found in a request.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidRequest, strings.Join(messages, "; "))
+}
+
+// Unwrap returns the individual validation errors, so that errors.As finds each *ValidationError.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Fields returns the names of the invalid fields.
+func (e ValidationErrors) Fields() []string {
+	fields := make([]string, 0, len(e))
+	for _, err := range e {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+// add appends a ValidationError for field.
+func (e *ValidationErrors) add(field string, value any, message string) {
+	*e = append(*e, &ValidationError{Field: field, Value: value, Message: message})
+}
+
+// err returns e as an error, or nil when no field is invalid.
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
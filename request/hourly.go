@@ -0,0 +1,172 @@
+package request
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoHourlyMix is returned by HourlyMixProvider when it has no data for a geo and hour and no fallback.
+var ErrNoHourlyMix = errors.New("no hourly electricity mix")
+
+// hourlyFactors are the factors of one hour. ADPe and PE are optional in hourly datasets, which often only
+// publish carbon intensity.
+type hourlyFactors struct {
+	adpe, gwp, pe  float64
+	hasADPe, hasPE bool
+}
+
+// HourlyMixProvider is an ElectricityMixProvider backed by historical hourly data, eg to re-estimate past traffic
+// with the carbon intensity at the time of each request.
+//
+// Requests without a timestamp, or for a geo and hour missing from the data, use the fallback provider. Hours
+// that only give GWP take ADPe and PE from the fallback provider.
+type HourlyMixProvider struct {
+	fallback ElectricityMixProvider
+	// hours maps a normalized geo to the factors of each hour, keyed by the Unix time of the start of the hour.
+	hours map[string]map[int64]hourlyFactors
+}
+
+// NewHourlyMixProvider reads a CSV dataset with geo, datetime and gwp columns and optional adpe and pe columns.
+// Datetimes are RFC 3339 and are truncated to the hour. fallback may be nil when every row has every factor,
+// in which case lookups outside the data fail with ErrNoHourlyMix.
+func NewHourlyMixProvider(source string, fallback ElectricityMixProvider) (*HourlyMixProvider, error) {
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hourly electricity mixes: %w", err)
+	}
+	defer f.Close()
+	p, err := ParseHourlyMixes(f, fallback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse hourly electricity mixes %q: %w", source, err)
+	}
+	return p, nil
+}
+
+// ParseHourlyMixes reads a CSV dataset in the format of NewHourlyMixProvider.
+func ParseHourlyMixes(r io.Reader, fallback ElectricityMixProvider) (*HourlyMixProvider, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV is empty")
+	}
+
+	index := make(map[string]int)
+	for i, column := range records[0] {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range []string{"geo", "datetime", "gwp"} {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("column %q is missing", column)
+		}
+	}
+	if fallback == nil {
+		for _, column := range []string{"adpe", "pe"} {
+			if _, ok := index[column]; !ok {
+				return nil, fmt.Errorf("column %q is required without a fallback provider", column)
+			}
+		}
+	}
+
+	p := &HourlyMixProvider{fallback: fallback, hours: make(map[string]map[int64]hourlyFactors)}
+	for line, record := range records[1:] {
+		geo := NormalizeGeo(record[index["geo"]])
+		at, err := time.Parse(time.RFC3339, strings.TrimSpace(record[index["datetime"]]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid datetime: %w", line+2, err)
+		}
+
+		var factors hourlyFactors
+		for _, factor := range []struct {
+			column string
+			value  *float64
+			set    *bool
+		}{
+			{"adpe", &factors.adpe, &factors.hasADPe},
+			{"gwp", &factors.gwp, nil},
+			{"pe", &factors.pe, &factors.hasPE},
+		} {
+			i, ok := index[factor.column]
+			if !ok || strings.TrimSpace(record[i]) == "" {
+				if factor.set == nil || fallback == nil {
+					return nil, fmt.Errorf("line %d: %s value is missing", line+2, factor.column)
+				}
+				continue
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(record[i]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s value: %w", line+2, factor.column, err)
+			}
+			if value < 0 {
+				return nil, fmt.Errorf("line %d: %s cannot be negative", line+2, factor.column)
+			}
+			*factor.value = value
+			if factor.set != nil {
+				*factor.set = true
+			}
+		}
+
+		if p.hours[geo] == nil {
+			p.hours[geo] = make(map[int64]hourlyFactors)
+		}
+		p.hours[geo][hourKey(at)] = factors
+	}
+	return p, nil
+}
+
+// ElectricityMix returns the mix of geo during the hour that contains at. Regions without hourly data use the
+// hourly data of their parent geo before falling back to the fallback provider.
+func (p *HourlyMixProvider) ElectricityMix(ctx context.Context, geo string, at time.Time) (ElectricityMix, error) {
+	if at.IsZero() {
+		return p.fallbackMix(ctx, geo, at)
+	}
+	requested := NormalizeGeo(geo)
+	for key := requested; key != ""; key = parentGeo(key) {
+		factors, ok := p.hours[key][hourKey(at)]
+		if !ok {
+			continue
+		}
+		mix := ElectricityMix{
+			Geo:          key,
+			RequestedGeo: requested,
+			ADPe:         factors.adpe,
+			GWP:          factors.gwp,
+			PE:           factors.pe,
+			Hour:         at.UTC().Truncate(time.Hour),
+		}
+		if !factors.hasADPe || !factors.hasPE {
+			average, err := p.fallbackMix(ctx, key, at)
+			if err != nil {
+				return ElectricityMix{}, err
+			}
+			if !factors.hasADPe {
+				mix.ADPe = average.ADPe
+			}
+			if !factors.hasPE {
+				mix.PE = average.PE
+			}
+		}
+		return mix, nil
+	}
+	return p.fallbackMix(ctx, geo, at)
+}
+
+func (p *HourlyMixProvider) fallbackMix(ctx context.Context, geo string, at time.Time) (ElectricityMix, error) {
+	if p.fallback == nil {
+		return ElectricityMix{}, fmt.Errorf("%w for %q at %s", ErrNoHourlyMix, geo, at.UTC().Format(time.RFC3339))
+	}
+	return p.fallback.ElectricityMix(ctx, geo, at)
+}
+
+// hourKey returns the Unix time of the start of the hour that contains t.
+func hourKey(t time.Time) int64 {
+	return t.UTC().Truncate(time.Hour).Unix()
+}
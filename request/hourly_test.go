@@ -0,0 +1,158 @@
+package request
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHourlyMixProvider_ElectricityMix(t *testing.T) {
+	hourly := "geo,datetime,gwp,adpe,pe\n" +
+		"FR,2025-01-15T10:00:00Z,0.035,5e-08,11\n" +
+		"FR,2025-01-15T12:00:00+01:00,0.02,5e-08,11\n" +
+		"US-CAISO,2025-01-15T10:00:00Z,0.3,6e-08,10\n" +
+		"USA,2025-01-15T10:00:00Z,0.5,9e-08,11\n"
+	gwpOnly := "geo,datetime,gwp\nFR,2025-01-15T10:00:00Z,0.035\n"
+	fallback := NewMixDatabase([]ElectricityMix{{Geo: "FRA", ADPe: 4e-08, GWP: 0.08, PE: 12}})
+	hour := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		csv           string
+		fallback      ElectricityMixProvider
+		geo           string
+		at            time.Time
+		want          ElectricityMix
+		expectedError string
+	}{
+		{
+			name: "should return the mix of the hour that contains the time",
+			csv:  hourly,
+			geo:  "FRA",
+			at:   hour.Add(42 * time.Minute),
+			want: ElectricityMix{Geo: "FRA", RequestedGeo: "FRA", ADPe: 5e-08, GWP: 0.035, PE: 11, Hour: hour},
+		},
+		{
+			name: "should compare times across time zones",
+			csv:  hourly,
+			geo:  "FR",
+			at:   time.Date(2025, 1, 15, 11, 30, 0, 0, time.FixedZone("CET", 3600)),
+			want: ElectricityMix{Geo: "FRA", RequestedGeo: "FRA", ADPe: 5e-08, GWP: 0.035, PE: 11, Hour: hour},
+		},
+		{
+			name: "should return the mix of regions",
+			csv:  hourly,
+			geo:  "US-CAISO",
+			at:   hour,
+			want: ElectricityMix{Geo: "US-CAISO", RequestedGeo: "US-CAISO", ADPe: 6e-08, GWP: 0.3, PE: 10, Hour: hour},
+		},
+		{
+			name: "should fall back to the hourly mix of the country",
+			csv:  hourly,
+			geo:  "US-ERCOT",
+			at:   hour,
+			want: ElectricityMix{Geo: "USA", RequestedGeo: "US-ERCOT", ADPe: 9e-08, GWP: 0.5, PE: 11, Hour: hour},
+		},
+		{
+			name:     "should take missing factors from the fallback",
+			csv:      gwpOnly,
+			fallback: fallback,
+			geo:      "FR",
+			at:       hour,
+			want:     ElectricityMix{Geo: "FRA", RequestedGeo: "FRA", ADPe: 4e-08, GWP: 0.035, PE: 12, Hour: hour},
+		},
+		{
+			name:     "should use the fallback outside of the data",
+			csv:      gwpOnly,
+			fallback: fallback,
+			geo:      "FR",
+			at:       hour.Add(time.Hour),
+			want:     ElectricityMix{Geo: "FRA", RequestedGeo: "FRA", ADPe: 4e-08, GWP: 0.08, PE: 12},
+		},
+		{
+			name:     "should use the fallback without a time",
+			csv:      hourly,
+			fallback: fallback,
+			geo:      "FR",
+			want:     ElectricityMix{Geo: "FRA", RequestedGeo: "FRA", ADPe: 4e-08, GWP: 0.08, PE: 12},
+		},
+		{
+			name:          "should fail outside of the data without a fallback",
+			csv:           hourly,
+			geo:           "FR",
+			at:            hour.Add(-time.Hour),
+			expectedError: `no hourly electricity mix for "FR" at 2025-01-15T09:00:00Z`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParseHourlyMixes(strings.NewReader(tt.csv), tt.fallback)
+			require.NoError(t, err)
+
+			got, err := p.ElectricityMix(context.Background(), tt.geo, tt.at)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.ErrorIs(t, err, ErrNoHourlyMix)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewHourlyMixProvider(t *testing.T) {
+	fallback := NewMixDatabase(nil)
+	tests := []struct {
+		name          string
+		csv           string
+		fallback      ElectricityMixProvider
+		expectedError string
+	}{
+		{
+			name:          "should reject missing columns",
+			csv:           "geo,gwp\nFR,0.03\n",
+			fallback:      fallback,
+			expectedError: `column "datetime" is missing`,
+		},
+		{
+			name:          "should require every factor without a fallback",
+			csv:           "geo,datetime,gwp\nFR,2025-01-15T10:00:00Z,0.03\n",
+			expectedError: `column "adpe" is required without a fallback provider`,
+		},
+		{
+			name:          "should require gwp values",
+			csv:           "geo,datetime,gwp\nFR,2025-01-15T10:00:00Z,\n",
+			fallback:      fallback,
+			expectedError: "line 2: gwp value is missing",
+		},
+		{
+			name:     "should reject invalid datetimes",
+			csv:      "geo,datetime,gwp\nFR,2025-01-15 10:00,0.03\n",
+			fallback: fallback,
+			expectedError: `line 2: invalid datetime: parsing time "2025-01-15 10:00" as "2006-01-02T15:04:05Z07:00": ` +
+				`cannot parse " 10:00" as "T"`,
+		},
+		{
+			name:          "should reject negative values",
+			csv:           "geo,datetime,gwp\nFR,2025-01-15T10:00:00Z,-1\n",
+			fallback:      fallback,
+			expectedError: "line 2: gwp cannot be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := filepath.Join(t.TempDir(), "hourly.csv")
+			require.NoError(t, os.WriteFile(source, []byte(tt.csv), 0o600))
+
+			_, err := NewHourlyMixProvider(source, tt.fallback)
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package request
+
+import "time"
+
+// DefaultImageSteps is the number of denoising steps assumed when an image request does not report it, as
+// hosted APIs such as DALL·E and Imagen do not expose it.
+const DefaultImageSteps = 50
+
+// ImageRequest describes a call to an image generation model.
+type ImageRequest struct {
+	ImageCount int
+	// Width and Height are the resolution of each image in pixels.
+	Width  int
+	Height int
+	// Steps is the number of denoising steps per image. Zero means DefaultImageSteps.
+	Steps   int
+	Latency time.Duration
+	Location
+}
+
+// Megapixels returns the resolution of each image in megapixels.
+func (r *ImageRequest) Megapixels() float64 {
+	return float64(r.Width) * float64(r.Height) / 1e6
+}
+
+// DiffusionSteps returns the number of denoising steps per image.
+func (r *ImageRequest) DiffusionSteps() int {
+	if r.Steps == 0 {
+		return DefaultImageSteps
+	}
+	return r.Steps
+}
+
+// Validate checks every field of the request and returns ValidationErrors listing the invalid ones.
+func (r *ImageRequest) Validate() error {
+	var errs ValidationErrors
+	if r.ImageCount <= 0 {
+		errs.add("ImageCount", r.ImageCount, "must be greater than 0")
+	}
+	if r.Width <= 0 {
+		errs.add("Width", r.Width, "must be greater than 0")
+	}
+	if r.Height <= 0 {
+		errs.add("Height", r.Height, "must be greater than 0")
+	}
+	if r.Steps < 0 {
+		errs.add("Steps", r.Steps, "cannot be negative")
+	}
+	if r.Latency <= 0 {
+		errs.add("Latency", r.Latency, "must be greater than 0")
+	}
+	r.validate(&errs)
+	return errs.err()
+}
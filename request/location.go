@@ -0,0 +1,50 @@
+package request
+
+import "time"
+
+// Location tells where and when a request was served, which selects the electricity mix of its usage impacts. It
+// is embedded in EmbeddingRequest, ImageRequest, TranscriptionRequest and SpeechRequest, and Request has the same
+// fields.
+type Location struct {
+	// Geo is an ISO 3166 country code such as "FR" or "FRA", or a region such as "US-CAISO". It is optional.
+	Geo string
+	// Timestamp is when the request was made. It is optional.
+	Timestamp time.Time
+	// ProviderRegion is the cloud region that served the request, eg "us-east-1". It is optional and locates the
+	// request when Geo is empty.
+	ProviderRegion string
+	// CloudProvider is the provider of ProviderRegion, eg AWS. It is optional unless several providers have a
+	// region with that name.
+	CloudProvider CloudProvider
+}
+
+// ResolveGeo returns the geo the request was served from: Geo when set, otherwise the geo of ProviderRegion.
+// It returns "" when neither is set.
+func (l *Location) ResolveGeo() (string, error) {
+	if l.Geo != "" || l.ProviderRegion == "" {
+		return l.Geo, nil
+	}
+	cloudRegion, err := LookupCloudRegion(l.CloudProvider, l.ProviderRegion)
+	if err != nil {
+		return "", err
+	}
+	return cloudRegion.Geo, nil
+}
+
+// GetElectricityMix returns the electricity mix of the resolved geo from the default database. It returns an error
+// wrapping ErrUnknownGeo when the geo is not in the dataset, or ErrUnknownRegion when the provider region is not
+// mapped to a geo.
+func (l *Location) GetElectricityMix() (ElectricityMix, error) {
+	geo, err := l.ResolveGeo()
+	if err != nil {
+		return ElectricityMix{}, err
+	}
+	return LookupElectricityMix(geo)
+}
+
+// validate adds the invalid fields of l to errs.
+func (l *Location) validate(errs *ValidationErrors) {
+	validateGeo(errs, l.Geo)
+	validateTimestamp(errs, l.Timestamp)
+	validateProviderRegion(errs, l.CloudProvider, l.ProviderRegion)
+}
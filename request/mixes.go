@@ -0,0 +1,261 @@
+package request
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// embeddedMixesPath is the path of the default electricity mix dataset inside embeddedMixes.
+	embeddedMixesPath = "data/electricity_mixes.csv"
+	// WorldGeo is the geo of the world average electricity mix, used when a request has no geo.
+	WorldGeo = "WORLD"
+)
+
+// The dataset holds the impact factors per kWh of the USA, France and world averages as published by
+// EcoLogits. Lookups of other countries fail with ErrUnknownGeo until their mixes are loaded with
+// LoadElectricityMixes or the file is regenerated from upstream with `catalog sync -mixes`. It has no
+// sub-national rows, so regions such as "US-CAISO" fall back to their country.
+//
+//go:embed data/electricity_mixes.csv
+var embeddedMixes embed.FS
+
+// ElectricityMixColumns returns the columns of the electricity mix dataset, in order.
+func ElectricityMixColumns() []string {
+	return []string{"geo", "adpe", "gwp", "pe"}
+}
+
+// geoAliases maps common non-ISO geo codes to the code that keys the dataset.
+var geoAliases = map[string]string{
+	"UK":  "GBR",
+	"WOR": WorldGeo,
+}
+
+var (
+	defaultMixesOnce sync.Once
+	defaultMixesErr  error
+	defaultMixes     *MixDatabase
+)
+
+// ErrUnknownGeo is returned when no electricity mix is known for a geo.
+// Use errors.As with *UnknownGeoError to get the requested geo.
+var ErrUnknownGeo = errors.New("unknown geo")
+
+// UnknownGeoError describes a failed electricity mix lookup.
+type UnknownGeoError struct {
+	Geo string
+}
+
+func (e *UnknownGeoError) Error() string {
+	return fmt.Sprintf("%s: no electricity mix for %q", ErrUnknownGeo, e.Geo)
+}
+
+// Is reports whether target is ErrUnknownGeo.
+func (e *UnknownGeoError) Is(target error) bool {
+	return target == ErrUnknownGeo
+}
+
+// MixDatabase is a concurrency-safe index of electricity mixes keyed by ISO 3166-1 alpha-3 country code, or by
+// alpha-2 country code followed by a region code for sub-national mixes, eg "US-CAISO".
+type MixDatabase struct {
+	mu    sync.RWMutex
+	mixes map[string]ElectricityMix
+}
+
+// NewMixDatabase returns a database populated with mixes.
+func NewMixDatabase(mixes []ElectricityMix) *MixDatabase {
+	d := &MixDatabase{mixes: make(map[string]ElectricityMix, len(mixes))}
+	d.Add(mixes)
+	return d
+}
+
+// DefaultMixDatabase returns the process-wide database, parsing the embedded dataset on first use.
+func DefaultMixDatabase() (*MixDatabase, error) {
+	defaultMixesOnce.Do(func() {
+		mixes, err := parseEmbeddedMixes(embeddedMixesPath)
+		if err != nil {
+			defaultMixesErr = err
+			return
+		}
+		defaultMixes = NewMixDatabase(mixes)
+	})
+	return defaultMixes, defaultMixesErr
+}
+
+// parseEmbeddedMixes parses a dataset compiled into the binary.
+func parseEmbeddedMixes(path string) ([]ElectricityMix, error) {
+	f, err := embeddedMixes.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded electricity mixes: %w", err)
+	}
+	defer f.Close()
+	mixes, err := ParseElectricityMixes(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse embedded electricity mixes %q: %w", path, err)
+	}
+	return mixes, nil
+}
+
+// LoadElectricityMixes reads a CSV dataset and adds its mixes to the default database, replacing the mixes
+// of the geos it lists.
+func LoadElectricityMixes(source string) error {
+	d, err := DefaultMixDatabase()
+	if err != nil {
+		return err
+	}
+	return d.LoadCSV(source)
+}
+
+// Add inserts mixes, replacing existing mixes with the same geo.
+func (d *MixDatabase) Add(mixes []ElectricityMix) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, mix := range mixes {
+		d.mixes[mix.Geo] = mix
+	}
+}
+
+// LoadCSV reads a CSV dataset with geo, adpe, gwp and pe columns and adds its mixes to the database.
+func (d *MixDatabase) LoadCSV(source string) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open electricity mixes: %w", err)
+	}
+	defer f.Close()
+	mixes, err := ParseElectricityMixes(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse electricity mixes %q: %w", source, err)
+	}
+	d.Add(mixes)
+	return nil
+}
+
+// Lookup returns the electricity mix of geo, which is an ISO 3166-1 alpha-2 or alpha-3 country code, optionally
+// followed by region codes, eg "US-CAISO". An empty geo resolves to the world average. A region missing from
+// the database falls back to its parent region, then to its country; the returned mix then reports the geo it
+// was resolved to and Fallback returns true.
+func (d *MixDatabase) Lookup(geo string) (ElectricityMix, error) {
+	requested := NormalizeGeo(geo)
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for key := requested; key != ""; key = parentGeo(key) {
+		if mix, ok := d.mixes[key]; ok {
+			mix.RequestedGeo = requested
+			return mix, nil
+		}
+	}
+	return ElectricityMix{}, &UnknownGeoError{Geo: geo}
+}
+
+// Geos returns the geos known to the database, sorted.
+func (d *MixDatabase) Geos() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return slices.Sorted(maps.Keys(d.mixes))
+}
+
+// NormalizeGeo returns the code that keys geo in the dataset: alpha-2 country codes are converted to alpha-3,
+// regions are prefixed with the alpha-2 code of their country, eg "CA-QC", and an empty geo is the world average.
+func NormalizeGeo(geo string) string {
+	geo = strings.ToUpper(strings.TrimSpace(geo))
+	if geo == "" {
+		return WorldGeo
+	}
+	if country, region, ok := strings.Cut(geo, "-"); ok {
+		if alpha2, ok := alpha2Codes[normalizeCountry(country)]; ok {
+			country = alpha2
+		}
+		return country + "-" + region
+	}
+	return normalizeCountry(geo)
+}
+
+// normalizeCountry returns the alpha-3 code of an upper-case country code or alias, or the code itself when it is
+// not known.
+func normalizeCountry(country string) string {
+	if alias, ok := geoAliases[country]; ok {
+		return alias
+	}
+	if alpha3, ok := alpha3Codes[country]; ok {
+		return alpha3
+	}
+	return country
+}
+
+// parentGeo returns the geo that contains a normalized geo, eg "US-CAISO" for "US-CAISO-NORTH" and "USA" for
+// "US-CAISO", or "" for countries.
+func parentGeo(geo string) string {
+	i := strings.LastIndex(geo, "-")
+	if i < 0 {
+		return ""
+	}
+	return NormalizeGeo(geo[:i])
+}
+
+// ParseElectricityMixes reads a CSV dataset whose header names the geo, adpe, gwp and pe columns, in any order. The
+// geo column may also be named "name", as in the upstream EcoLogits dataset.
+func ParseElectricityMixes(r io.Reader) ([]ElectricityMix, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("CSV is empty")
+	}
+
+	index := make(map[string]int)
+	for i, column := range records[0] {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column == "name" {
+			column = "geo"
+		}
+		index[column] = i
+	}
+	for _, column := range ElectricityMixColumns() {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("column %q is missing", column)
+		}
+	}
+
+	mixes := make([]ElectricityMix, 0, len(records)-1)
+	for line, record := range records[1:] {
+		mix := ElectricityMix{Geo: NormalizeGeo(record[index["geo"]])}
+		for _, factor := range []struct {
+			column string
+			value  *float64
+		}{
+			{"adpe", &mix.ADPe},
+			{"gwp", &mix.GWP},
+			{"pe", &mix.PE},
+		} {
+			value, err := strconv.ParseFloat(strings.TrimSpace(record[index[factor.column]]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s value: %w", line+2, factor.column, err)
+			}
+			if value < 0 {
+				return nil, fmt.Errorf("line %d: %s cannot be negative", line+2, factor.column)
+			}
+			*factor.value = value
+		}
+		mixes = append(mixes, mix)
+	}
+	return mixes, nil
+}
+
+// LookupElectricityMix returns the electricity mix of geo from the default database.
+func LookupElectricityMix(geo string) (ElectricityMix, error) {
+	d, err := DefaultMixDatabase()
+	if err != nil {
+		return ElectricityMix{}, err
+	}
+	return d.Lookup(geo)
+}
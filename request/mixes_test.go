@@ -0,0 +1,201 @@
+package request
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMixDatabase_Lookup(t *testing.T) {
+	d := NewMixDatabase([]ElectricityMix{
+		{Geo: "USA", ADPe: 0.0000000985548, GWP: 0.67978, PE: 11.358},
+		{Geo: "FRA", ADPe: 4.85798e-08, GWP: 0.0812, PE: 11.289},
+		{Geo: WorldGeo, ADPe: 7.37708e-08, GWP: 0.590478, PE: 9.988},
+		{Geo: "GBR", ADPe: 6e-08, GWP: 0.24, PE: 10.056},
+		{Geo: "CA-QC", ADPe: 4.215e-08, GWP: 0.002, PE: 9.7908},
+		{Geo: "US-CAISO", ADPe: 5.925e-08, GWP: 0.23, PE: 9.942},
+	})
+	tests := []struct {
+		name          string
+		geo           string
+		want          ElectricityMix
+		expectedError string
+	}{
+		{
+			name: "should look up alpha-3 codes",
+			geo:  "USA",
+			want: ElectricityMix{Geo: "USA", RequestedGeo: "USA", ADPe: 0.0000000985548, GWP: 0.67978, PE: 11.358},
+		},
+		{
+			name: "should look up alpha-2 codes case-insensitively",
+			geo:  "fr",
+			want: ElectricityMix{Geo: "FRA", RequestedGeo: "FRA", ADPe: 4.85798e-08, GWP: 0.0812, PE: 11.289},
+		},
+		{
+			name: "should fall back to the world average without a geo",
+			geo:  "",
+			want: ElectricityMix{Geo: WorldGeo, RequestedGeo: WorldGeo, ADPe: 7.37708e-08, GWP: 0.590478, PE: 9.988},
+		},
+		{
+			name: "should resolve common aliases",
+			geo:  "UK",
+			want: ElectricityMix{Geo: "GBR", RequestedGeo: "GBR", ADPe: 6e-08, GWP: 0.24, PE: 10.056},
+		},
+		{
+			name: "should look up regions",
+			geo:  "ca-qc",
+			want: ElectricityMix{Geo: "CA-QC", RequestedGeo: "CA-QC", ADPe: 4.215e-08, GWP: 0.002, PE: 9.7908},
+		},
+		{
+			name: "should look up regions of alpha-3 countries",
+			geo:  "USA-CAISO",
+			want: ElectricityMix{Geo: "US-CAISO", RequestedGeo: "US-CAISO", ADPe: 5.925e-08, GWP: 0.23, PE: 9.942},
+		},
+		{
+			name: "should fall back to the parent region",
+			geo:  "US-CAISO-NORTH",
+			want: ElectricityMix{Geo: "US-CAISO", RequestedGeo: "US-CAISO-NORTH", ADPe: 5.925e-08, GWP: 0.23, PE: 9.942},
+		},
+		{
+			name: "should fall back to the country",
+			geo:  "FR-IDF",
+			want: ElectricityMix{Geo: "FRA", RequestedGeo: "FR-IDF", ADPe: 4.85798e-08, GWP: 0.0812, PE: 11.289},
+		},
+		{
+			name:          "should reject regions of unknown countries",
+			geo:           "XX-NORTH",
+			expectedError: `unknown geo: no electricity mix for "XX-NORTH"`,
+		},
+		{
+			name:          "should reject unknown geos",
+			geo:           "XYZ",
+			expectedError: `unknown geo: no electricity mix for "XYZ"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := d.Lookup(tt.geo)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.ErrorIs(t, err, ErrUnknownGeo)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want.Geo != tt.want.RequestedGeo, got.Fallback())
+		})
+	}
+}
+
+func TestLookupElectricityMix(t *testing.T) {
+	for _, geo := range []string{"US", "fr", "", "US-CAISO"} {
+		mix, err := LookupElectricityMix(geo)
+		if assert.NoError(t, err, geo) {
+			assert.Positive(t, mix.GWP, geo)
+		}
+	}
+	_, err := LookupElectricityMix("DE")
+	assert.ErrorIs(t, err, ErrUnknownGeo, "countries without a sourced mix must not resolve")
+}
+
+func TestDefaultMixDatabase_ValidGeos(t *testing.T) {
+	d, err := DefaultMixDatabase()
+	if !assert.NoError(t, err) {
+		return
+	}
+	countries := slices.Collect(maps.Values(alpha3Codes))
+	for _, geo := range d.Geos() {
+		if geo == WorldGeo {
+			continue
+		}
+		if country, _, ok := strings.Cut(geo, "-"); ok {
+			assert.Contains(t, alpha3Codes, country, "dataset region %q is not in an ISO 3166 country", geo)
+			assert.Contains(t, d.Geos(), alpha3Codes[country], "dataset region %q has no country mix", geo)
+			continue
+		}
+		assert.Contains(t, countries, geo, "dataset geo %q is not an ISO 3166 country", geo)
+		mix, _ := d.Lookup(geo)
+		assert.Positive(t, mix.GWP, geo)
+		assert.Positive(t, mix.ADPe, geo)
+		assert.Positive(t, mix.PE, geo)
+	}
+}
+
+func TestMixDatabase_LoadCSV(t *testing.T) {
+	tests := []struct {
+		name          string
+		csv           string
+		want          []ElectricityMix
+		expectedError string
+	}{
+		{
+			name: "should add and replace mixes",
+			csv:  "pe,gwp,adpe,geo\n12,0.05,5e-08,fr\n10,0.1,6e-08,ZZZ\n",
+			want: []ElectricityMix{
+				{Geo: "FRA", RequestedGeo: "FRA", ADPe: 5e-08, GWP: 0.05, PE: 12},
+				{Geo: "ZZZ", RequestedGeo: "ZZZ", ADPe: 6e-08, GWP: 0.1, PE: 10},
+			},
+		},
+		{
+			name: "should read the upstream geo column",
+			csv:  "name,adpe,pe,gwp\nWOR,7e-08,10,0.6\n",
+			want: []ElectricityMix{{Geo: WorldGeo, RequestedGeo: WorldGeo, ADPe: 7e-08, GWP: 0.6, PE: 10}},
+		},
+		{
+			name:          "should reject missing columns",
+			csv:           "geo,gwp,pe\nFRA,0.05,12\n",
+			expectedError: `column "adpe" is missing`,
+		},
+		{
+			name:          "should reject invalid values",
+			csv:           "geo,adpe,gwp,pe\nFRA,5e-08,high,12\n",
+			expectedError: `line 2: invalid gwp value: strconv.ParseFloat: parsing "high": invalid syntax`,
+		},
+		{
+			name:          "should reject negative values",
+			csv:           "geo,adpe,gwp,pe\nFRA,5e-08,-0.1,12\n",
+			expectedError: "line 2: gwp cannot be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := filepath.Join(t.TempDir(), "mixes.csv")
+			if !assert.NoError(t, os.WriteFile(source, []byte(tt.csv), 0o600)) {
+				return
+			}
+			d := NewMixDatabase([]ElectricityMix{{Geo: "FRA", ADPe: 1, GWP: 1, PE: 1}})
+			err := d.LoadCSV(source)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			for _, want := range tt.want {
+				got, err := d.Lookup(want.Geo)
+				assert.NoError(t, err)
+				assert.Equal(t, want, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeGeo(t *testing.T) {
+	for geo, want := range map[string]string{
+		"":         WorldGeo,
+		"wor":      WorldGeo,
+		" de ":     "DEU",
+		"DEU":      "DEU",
+		"uk":       "GBR",
+		"us-cal":   "US-CAL",
+		"USA-CAL":  "US-CAL",
+		"uk-sct":   "GB-SCT",
+		"CAN-QC-X": "CA-QC-X",
+	} {
+		assert.Equal(t, want, NormalizeGeo(geo), strings.TrimSpace(geo))
+	}
+}
@@ -0,0 +1,173 @@
+package request
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// geoPattern matches country codes, optionally followed by subregion codes, eg "USA", "FR" or "US-CAISO", as
+// well as "WORLD".
+var geoPattern = regexp.MustCompile(`^[A-Z]{2,5}(-[A-Z0-9]+)*$`)
+
+// maxClockSkew is how far in the future a request timestamp may be, to tolerate clock differences between
+// the caller and this process.
+const maxClockSkew = 5 * time.Minute
+
+// Option configures a Request built with New.
+type Option func(*Request)
+
+// New builds a Request from options and validates it. It returns ValidationErrors listing every invalid field,
+// so that bad telemetry is rejected at ingestion.
+func New(opts ...Option) (Request, error) {
+	var r Request
+	for _, opt := range opts {
+		opt(&r)
+	}
+	if err := r.Validate(); err != nil {
+		return Request{}, err
+	}
+	return r, nil
+}
+
+// WithOutputTokens sets the number of generated tokens.
+func WithOutputTokens(count int64) Option {
+	return func(r *Request) { r.OutputTokenCount = float64(count) }
+}
+
+// WithInputTokens sets the number of prompt tokens.
+func WithInputTokens(count int64) Option {
+	return func(r *Request) { r.InputTokenCount = float64(count) }
+}
+
+// WithCachedInputTokens sets the number of prompt tokens served from a prompt cache.
+func WithCachedInputTokens(count int64) Option {
+	return func(r *Request) { r.CachedInputTokenCount = float64(count) }
+}
+
+// WithLatency sets the wall-clock duration of the request.
+func WithLatency(latency time.Duration) Option {
+	return func(r *Request) { r.Latency = latency }
+}
+
+// WithStreamingLatency sets the time to first token and the mean inter-token latency of a streamed response.
+func WithStreamingLatency(timeToFirstToken, interTokenLatency time.Duration) Option {
+	return func(r *Request) {
+		r.TimeToFirstToken = timeToFirstToken
+		r.InterTokenLatency = interTokenLatency
+	}
+}
+
+// WithGeo sets the location of the data center that served the request. Codes are case-insensitive.
+func WithGeo(geo string) Option {
+	return func(r *Request) { r.Geo = strings.ToUpper(strings.TrimSpace(geo)) }
+}
+
+// WithTimestamp sets when the request was made.
+func WithTimestamp(timestamp time.Time) Option {
+	return func(r *Request) { r.Timestamp = timestamp }
+}
+
+// WithProviderRegion sets the cloud region that served the request, eg "us-east-1". Its geo is used when the
+// request has no geo.
+func WithProviderRegion(region string) Option {
+	return func(r *Request) { r.ProviderRegion = strings.TrimSpace(region) }
+}
+
+// WithCloudProvider sets the cloud provider of the provider region, eg AWS, for region names that several
+// providers use.
+func WithCloudProvider(provider CloudProvider) Option {
+	return func(r *Request) { r.CloudProvider = normalizeCloudProvider(provider) }
+}
+
+// WithTags adds labels to the request. Later tags replace earlier ones with the same key.
+func WithTags(tags map[string]string) Option {
+	return func(r *Request) {
+		if r.Tags == nil {
+			r.Tags = make(map[string]string, len(tags))
+		}
+		maps.Copy(r.Tags, tags)
+	}
+}
+
+// Validate checks every field of the request and returns ValidationErrors listing the invalid ones.
+func (r *Request) Validate() error {
+	var errs ValidationErrors
+	report := errs.add
+
+	if r.OutputTokenCount <= 0 {
+		report("OutputTokenCount", r.OutputTokenCount, "must be greater than 0")
+	}
+	if r.InputTokenCount < 0 {
+		report("InputTokenCount", r.InputTokenCount, "cannot be negative")
+	}
+	if r.CachedInputTokenCount < 0 {
+		report("CachedInputTokenCount", r.CachedInputTokenCount, "cannot be negative")
+	} else if r.CachedInputTokenCount > r.InputTokenCount && r.InputTokenCount >= 0 {
+		report("CachedInputTokenCount", r.CachedInputTokenCount, "cannot exceed InputTokenCount")
+	}
+	if r.Latency <= 0 {
+		report("Latency", r.Latency, "must be greater than 0")
+	}
+	if r.TimeToFirstToken < 0 {
+		report("TimeToFirstToken", r.TimeToFirstToken, "cannot be negative")
+	} else if r.Latency > 0 && r.TimeToFirstToken > r.Latency {
+		report("TimeToFirstToken", r.TimeToFirstToken, "cannot exceed Latency")
+	}
+	if r.InterTokenLatency < 0 {
+		report("InterTokenLatency", r.InterTokenLatency, "cannot be negative")
+	}
+	r.location().validate(&errs)
+	for key := range r.Tags {
+		if strings.TrimSpace(key) == "" {
+			report("Tags", key, "keys cannot be empty")
+		}
+	}
+	return errs.err()
+}
+
+// validateTimestamp reports timestamps in the future, beyond the tolerated clock skew.
+func validateTimestamp(errs *ValidationErrors, timestamp time.Time) {
+	if !timestamp.IsZero() && timestamp.After(time.Now().Add(maxClockSkew)) {
+		errs.add("Timestamp", timestamp, "cannot be in the future")
+	}
+}
+
+// validateProviderRegion reports region unless it is empty or a known cloud region of provider. An empty provider
+// is only valid when a single provider has a region with that name.
+func validateProviderRegion(errs *ValidationErrors, provider CloudProvider, region string) {
+	if region == "" {
+		return
+	}
+	cloudRegion, err := LookupCloudRegion(provider, region)
+	switch {
+	case errors.Is(err, ErrAmbiguousRegion):
+		errs.add("ProviderRegion", region, "is a region of several cloud providers, set CloudProvider")
+	case err != nil:
+		errs.add("ProviderRegion", region, "must be a known cloud region")
+	default:
+		if _, err := LookupElectricityMix(cloudRegion.Geo); errors.Is(err, ErrUnknownGeo) {
+			errs.add("ProviderRegion", region, "has no electricity mix, load the mix of "+cloudRegion.Geo+
+				" with LoadElectricityMixes")
+		}
+	}
+}
+
+// validateGeo reports geo unless it is empty, WORLD or a known ISO 3166 country code or alias, optionally followed
+// by a subregion, eg "FR", "FRA", "UK" or "US-CAISO", whose electricity mix or the mix of its country is in the
+// default database. Codes are case-insensitive.
+func validateGeo(errs *ValidationErrors, geo string) {
+	if geo == "" {
+		return
+	}
+	normalized := NormalizeGeo(geo)
+	if _, ok := Alpha2Code(normalized); !geoPattern.MatchString(normalized) || !ok && normalized != WorldGeo {
+		errs.add("Geo", geo, "must be an ISO 3166 country code, optionally followed by a subregion")
+		return
+	}
+	if _, err := LookupElectricityMix(normalized); errors.Is(err, ErrUnknownGeo) {
+		errs.add("Geo", geo, "has no electricity mix, load it with LoadElectricityMixes")
+	}
+}
@@ -0,0 +1,212 @@
+package request
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name          string
+		opts          []Option
+		want          Request
+		invalidFields []string
+	}{
+		{
+			name: "should build a valid request",
+			opts: []Option{
+				WithOutputTokens(100),
+				WithInputTokens(1000),
+				WithCachedInputTokens(200),
+				WithLatency(5 * time.Second),
+				WithStreamingLatency(300*time.Millisecond, 20*time.Millisecond),
+				WithGeo(" fr "),
+				WithTimestamp(now),
+				WithProviderRegion("eu-west-3"),
+				WithTags(map[string]string{"team": "search"}),
+				WithTags(map[string]string{"feature": "rag"}),
+			},
+			want: Request{
+				OutputTokenCount:      100,
+				InputTokenCount:       1000,
+				CachedInputTokenCount: 200,
+				Latency:               5 * time.Second,
+				TimeToFirstToken:      300 * time.Millisecond,
+				InterTokenLatency:     20 * time.Millisecond,
+				Geo:                   "FR",
+				Timestamp:             now,
+				ProviderRegion:        "eu-west-3",
+				Tags:                  map[string]string{"team": "search", "feature": "rag"},
+			},
+		},
+		{
+			name: "should accept subregions",
+			opts: []Option{WithOutputTokens(1), WithLatency(time.Second), WithGeo("us-caiso")},
+			want: Request{OutputTokenCount: 1, Latency: time.Second, Geo: "US-CAISO"},
+		},
+		{
+			name: "should accept country aliases",
+			opts: []Option{WithOutputTokens(1), WithLatency(time.Second), WithGeo("wor")},
+			want: Request{OutputTokenCount: 1, Latency: time.Second, Geo: "WOR"},
+		},
+		{
+			name:          "should reject countries without an electricity mix",
+			opts:          []Option{WithOutputTokens(1), WithLatency(time.Second), WithGeo("uk")},
+			invalidFields: []string{"Geo"},
+		},
+		{
+			name:          "should reject unknown countries",
+			opts:          []Option{WithOutputTokens(1), WithLatency(time.Second), WithGeo("ZZ")},
+			invalidFields: []string{"Geo"},
+		},
+		{
+			name:          "should reject subregions of unknown countries",
+			opts:          []Option{WithOutputTokens(1), WithLatency(time.Second), WithGeo("ZZ-CAISO")},
+			invalidFields: []string{"Geo"},
+		},
+		{
+			name:          "should require output tokens and latency",
+			opts:          nil,
+			invalidFields: []string{"OutputTokenCount", "Latency"},
+		},
+		{
+			name: "should report every invalid field",
+			opts: []Option{
+				WithOutputTokens(-1),
+				WithInputTokens(-5),
+				WithLatency(-time.Second),
+				WithStreamingLatency(-time.Second, -time.Millisecond),
+				WithGeo("United States"),
+				WithTimestamp(now.Add(time.Hour)),
+				WithProviderRegion("us east 1"),
+				WithTags(map[string]string{" ": "x"}),
+			},
+			invalidFields: []string{
+				"OutputTokenCount",
+				"InputTokenCount",
+				"Latency",
+				"TimeToFirstToken",
+				"InterTokenLatency",
+				"Geo",
+				"Timestamp",
+				"ProviderRegion",
+				"Tags",
+			},
+		},
+		{
+			name: "should reject more cached than input tokens",
+			opts: []Option{
+				WithOutputTokens(1),
+				WithInputTokens(10),
+				WithCachedInputTokens(20),
+				WithLatency(time.Second),
+			},
+			invalidFields: []string{"CachedInputTokenCount"},
+		},
+		{
+			name: "should reject a time to first token longer than the request",
+			opts: []Option{
+				WithOutputTokens(1),
+				WithLatency(time.Second),
+				WithStreamingLatency(2*time.Second, 0),
+			},
+			invalidFields: []string{"TimeToFirstToken"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.opts...)
+			if tt.invalidFields == nil {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidRequest)
+			var errs ValidationErrors
+			if assert.True(t, errors.As(err, &errs)) {
+				assert.Equal(t, tt.invalidFields, errs.Fields())
+			}
+			var fieldErr *ValidationError
+			assert.True(t, errors.As(err, &fieldErr))
+			assert.Equal(t, Request{}, got)
+		})
+	}
+}
+
+func TestValidationErrors_Error(t *testing.T) {
+	_, err := New(WithOutputTokens(0), WithLatency(time.Second), WithGeo("u$a"))
+	assert.EqualError(t, err,
+		"invalid request: OutputTokenCount: must be greater than 0 (got 0); "+
+			"Geo: must be an ISO 3166 country code, optionally followed by a subregion (got U$A)")
+
+	_, err = New(WithOutputTokens(1), WithLatency(time.Second), WithGeo("DE"))
+	assert.EqualError(t, err,
+		"invalid request: Geo: has no electricity mix, load it with LoadElectricityMixes (got DE)")
+}
+
+func TestValidate_NonChatRequests(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           interface{ Validate() error }
+		invalidFields []string
+	}{
+		{
+			name: "should accept valid embedding requests",
+			req:  &EmbeddingRequest{InputTokenCount: 100, BatchSize: 2, Latency: time.Second, Location: Location{Geo: "fr"}},
+		},
+		{
+			name:          "should report every invalid embedding field",
+			req:           &EmbeddingRequest{BatchSize: -1, Location: Location{Geo: "ZZ", ProviderRegion: "moon-south-1"}},
+			invalidFields: []string{"InputTokenCount", "BatchSize", "Latency", "Geo", "ProviderRegion"},
+		},
+		{
+			name: "should accept image requests with the default number of steps",
+			req: &ImageRequest{
+				ImageCount: 1, Width: 1024, Height: 1024, Latency: time.Second, Location: Location{Geo: "WORLD"},
+			},
+		},
+		{
+			name:          "should report every invalid image field",
+			req:           &ImageRequest{Steps: -1, Location: Location{Geo: "U$A"}},
+			invalidFields: []string{"ImageCount", "Width", "Height", "Steps", "Latency", "Geo"},
+		},
+		{
+			name: "should accept valid transcription requests",
+			req:  &TranscriptionRequest{AudioDuration: time.Minute, Latency: time.Second, Location: Location{Geo: "US-CAISO"}},
+		},
+		{
+			name: "should report every invalid transcription field",
+			req: &TranscriptionRequest{
+				AudioDuration: -time.Minute, Location: Location{Geo: "ZZZ", Timestamp: time.Now().Add(time.Hour)},
+			},
+			invalidFields: []string{"AudioDuration", "Latency", "Geo", "Timestamp"},
+		},
+		{
+			name: "should accept valid speech requests",
+			req:  &SpeechRequest{CharacterCount: 500, Latency: time.Second},
+		},
+		{
+			name:          "should report every invalid speech field",
+			req:           &SpeechRequest{Latency: -time.Second, Location: Location{Geo: "WORLD-X"}},
+			invalidFields: []string{"CharacterCount", "Latency", "Geo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.invalidFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrInvalidRequest)
+			var errs ValidationErrors
+			if assert.True(t, errors.As(err, &errs)) {
+				assert.Equal(t, tt.invalidFields, errs.Fields())
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package request
+
+import (
+	"context"
+	"time"
+)
+
+// ElectricityMixProvider returns the electricity mix of a geo at a point in time. Providers without time-varying
+// data return the average mix of the geo.
+type ElectricityMixProvider interface {
+	ElectricityMix(ctx context.Context, geo string, at time.Time) (ElectricityMix, error)
+}
+
+// ElectricityMix returns the average electricity mix of geo, regardless of at. It makes MixDatabase an
+// ElectricityMixProvider.
+func (d *MixDatabase) ElectricityMix(_ context.Context, geo string, _ time.Time) (ElectricityMix, error) {
+	return d.Lookup(geo)
+}
@@ -0,0 +1,181 @@
+package request
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CloudProvider is a cloud platform whose regions are mapped to geos.
+type CloudProvider string
+
+const (
+	AWS   CloudProvider = "aws"
+	Azure CloudProvider = "azure"
+	GCP   CloudProvider = "gcp"
+)
+
+// CloudRegion is a cloud provider region and the geo of the grid that powers it.
+type CloudRegion struct {
+	Provider CloudProvider
+	Name     string
+	Geo      string
+}
+
+// builtinCloudRegions maps the regions of the major cloud providers to the most specific geo that powers them: a
+// grid operator or state when known, the country otherwise. Only regions whose geo, or its country, is in the
+// embedded electricity mix dataset are built in; register the other regions with RegisterCloudRegion after loading
+// their mixes with LoadElectricityMixes.
+var builtinCloudRegions = []CloudRegion{
+	{AWS, "us-east-1", "US-PJM"},
+	{AWS, "us-east-2", "US-PJM"},
+	{AWS, "us-west-1", "US-CAISO"},
+	{AWS, "us-west-2", "US-BPA"},
+	{AWS, "us-gov-east-1", "US-PJM"},
+	{AWS, "us-gov-west-1", "US-BPA"},
+	{AWS, "eu-west-3", "FR"},
+
+	{Azure, "eastus", "US-PJM"},
+	{Azure, "eastus2", "US-PJM"},
+	{Azure, "centralus", "US-MISO"},
+	{Azure, "northcentralus", "US-PJM"},
+	{Azure, "southcentralus", "US-ERCOT"},
+	{Azure, "westcentralus", "US"},
+	{Azure, "westus", "US-CAISO"},
+	{Azure, "westus2", "US-BPA"},
+	{Azure, "westus3", "US"},
+	{Azure, "francecentral", "FR"},
+
+	{GCP, "us-central1", "US-MISO"},
+	{GCP, "us-east1", "US"},
+	{GCP, "us-east4", "US-PJM"},
+	{GCP, "us-east5", "US-PJM"},
+	{GCP, "us-south1", "US-ERCOT"},
+	{GCP, "us-west1", "US-BPA"},
+	{GCP, "us-west2", "US-CAISO"},
+	{GCP, "us-west3", "US"},
+	{GCP, "us-west4", "US"},
+	{GCP, "europe-west9", "FR"},
+}
+
+// providerGeos maps AI providers, named as aimodel.Provider, to the geo where they serve requests when the
+// request does not say where it was served. Providers that are deployed anywhere, eg huggingface_hub, have no
+// default.
+var providerGeos = map[string]string{
+	"anthropic": "US",
+	"cohere":    "US",
+	"google":    "US",
+	"mistralai": "FR",
+	"openai":    "US",
+}
+
+// regionKey identifies a cloud region. Region names are only unique within a cloud provider.
+type regionKey struct {
+	provider CloudProvider
+	name     string
+}
+
+var (
+	cloudRegionsMu sync.RWMutex
+	cloudRegions   = func() map[regionKey]CloudRegion {
+		regions := make(map[regionKey]CloudRegion, len(builtinCloudRegions))
+		for _, region := range builtinCloudRegions {
+			regions[regionKey{provider: region.Provider, name: region.Name}] = region
+		}
+		return regions
+	}()
+)
+
+var (
+	// ErrUnknownRegion is returned when a cloud region is not mapped to a geo.
+	ErrUnknownRegion = errors.New("unknown cloud region")
+	// ErrAmbiguousRegion is returned when a cloud region is looked up without a cloud provider and several
+	// providers have a region with that name.
+	ErrAmbiguousRegion = errors.New("ambiguous cloud region")
+)
+
+// RegisterCloudRegion adds or replaces the mapping of a cloud region to a geo, eg for new regions or private
+// data centers. Regions are keyed by provider and name; the provider may be empty for private data centers.
+func RegisterCloudRegion(region CloudRegion) error {
+	name := strings.ToLower(strings.TrimSpace(region.Name))
+	if name == "" {
+		return errors.New("region name cannot be empty")
+	}
+	if strings.TrimSpace(region.Geo) == "" {
+		return fmt.Errorf("geo of region %q cannot be empty", name)
+	}
+	region.Provider = normalizeCloudProvider(region.Provider)
+	region.Name = name
+	region.Geo = strings.ToUpper(strings.TrimSpace(region.Geo))
+	cloudRegionsMu.Lock()
+	defer cloudRegionsMu.Unlock()
+	cloudRegions[regionKey{provider: region.Provider, name: name}] = region
+	return nil
+}
+
+// LookupCloudRegion returns the mapping of a cloud region of provider, eg "us-east-1" of AWS, "francecentral" of
+// Azure or "europe-west9" of GCP. Provider and region names are case-insensitive. An empty provider matches the
+// region of any provider, and returns an error wrapping ErrAmbiguousRegion when several providers have a region
+// with that name.
+func LookupCloudRegion(provider CloudProvider, name string) (CloudRegion, error) {
+	provider = normalizeCloudProvider(provider)
+	name = strings.ToLower(strings.TrimSpace(name))
+	cloudRegionsMu.RLock()
+	defer cloudRegionsMu.RUnlock()
+	if provider != "" {
+		region, ok := cloudRegions[regionKey{provider: provider, name: name}]
+		if !ok {
+			return CloudRegion{}, fmt.Errorf("%w: %q of %s", ErrUnknownRegion, name, provider)
+		}
+		return region, nil
+	}
+
+	var matches []CloudRegion
+	for key, region := range cloudRegions {
+		if key.name == name {
+			matches = append(matches, region)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return CloudRegion{}, fmt.Errorf("%w: %q", ErrUnknownRegion, name)
+	case 1:
+		return matches[0], nil
+	default:
+		providers := make([]string, 0, len(matches))
+		for _, region := range matches {
+			providers = append(providers, string(region.Provider))
+		}
+		slices.Sort(providers)
+		return CloudRegion{}, fmt.Errorf("%w: %q is a region of %s, set the cloud provider", ErrAmbiguousRegion,
+			name, strings.Join(providers, ", "))
+	}
+}
+
+func normalizeCloudProvider(provider CloudProvider) CloudProvider {
+	return CloudProvider(strings.ToLower(strings.TrimSpace(string(provider))))
+}
+
+// CloudRegions returns the known cloud regions, sorted by provider and name.
+func CloudRegions() []CloudRegion {
+	cloudRegionsMu.RLock()
+	defer cloudRegionsMu.RUnlock()
+	return slices.SortedFunc(maps.Values(cloudRegions), func(a, b CloudRegion) int {
+		return cmp.Or(cmp.Compare(a.Provider, b.Provider), cmp.Compare(a.Name, b.Name))
+	})
+}
+
+// ProviderGeo returns the default geo of an AI provider, eg "US" for "openai".
+func ProviderGeo(provider string) (string, bool) {
+	geo, ok := providerGeos[strings.ToLower(provider)]
+	return geo, ok
+}
+
+// ResolveGeo returns the geo the request was served from, as Location.ResolveGeo.
+func (r *Request) ResolveGeo() (string, error) {
+	return r.location().ResolveGeo()
+}
@@ -0,0 +1,151 @@
+package request
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinCloudRegions_HaveElectricityMixes(t *testing.T) {
+	for _, region := range builtinCloudRegions {
+		_, err := LookupElectricityMix(region.Geo)
+		assert.NoError(t, err, "region %q of %s is mapped to %q", region.Name, region.Provider, region.Geo)
+	}
+}
+
+func TestLookupCloudRegion(t *testing.T) {
+	tests := []struct {
+		name          string
+		provider      CloudProvider
+		region        string
+		want          CloudRegion
+		expectedError string
+	}{
+		{
+			name:     "should look up AWS regions",
+			provider: AWS,
+			region:   "us-east-1",
+			want:     CloudRegion{Provider: AWS, Name: "us-east-1", Geo: "US-PJM"},
+		},
+		{
+			name:   "should look up Azure regions case-insensitively",
+			region: "FranceCentral",
+			want:   CloudRegion{Provider: Azure, Name: "francecentral", Geo: "FR"},
+		},
+		{
+			name:     "should look up GCP regions",
+			provider: "GCP",
+			region:   "europe-west9",
+			want:     CloudRegion{Provider: GCP, Name: "europe-west9", Geo: "FR"},
+		},
+		{
+			name:          "should reject regions of another provider",
+			provider:      AWS,
+			region:        "francecentral",
+			expectedError: `unknown cloud region: "francecentral" of aws`,
+		},
+		{
+			name:          "should reject unknown regions",
+			region:        "moon-south-1",
+			expectedError: `unknown cloud region: "moon-south-1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LookupCloudRegion(tt.provider, tt.region)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				assert.ErrorIs(t, err, ErrUnknownRegion)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegisterCloudRegion(t *testing.T) {
+	assert.EqualError(t, RegisterCloudRegion(CloudRegion{Geo: "FR"}), "region name cannot be empty")
+	assert.EqualError(t, RegisterCloudRegion(CloudRegion{Name: "dc-1"}), `geo of region "dc-1" cannot be empty`)
+
+	assert.NoError(t, RegisterCloudRegion(CloudRegion{Name: " Paris-DC-1 ", Geo: "fr"}))
+	got, err := LookupCloudRegion("", "paris-dc-1")
+	assert.NoError(t, err)
+	assert.Equal(t, CloudRegion{Name: "paris-dc-1", Geo: "FR"}, got)
+
+	assert.NoError(t, RegisterCloudRegion(CloudRegion{Provider: "Acme", Name: "central-1", Geo: "FR"}))
+	assert.NoError(t, RegisterCloudRegion(CloudRegion{Provider: "globex", Name: "central-1", Geo: "US"}))
+	_, err = LookupCloudRegion("", "central-1")
+	assert.EqualError(t, err,
+		`ambiguous cloud region: "central-1" is a region of acme, globex, set the cloud provider`)
+	assert.ErrorIs(t, err, ErrAmbiguousRegion)
+	got, err = LookupCloudRegion("acme", "central-1")
+	assert.NoError(t, err)
+	assert.Equal(t, CloudRegion{Provider: "acme", Name: "central-1", Geo: "FR"}, got)
+
+	_, err = New(WithOutputTokens(10), WithLatency(time.Second), WithProviderRegion("central-1"))
+	assert.EqualError(t, err, "invalid request: ProviderRegion: is a region of several cloud providers, "+
+		"set CloudProvider (got central-1)")
+	req, err := New(WithOutputTokens(10), WithLatency(time.Second), WithProviderRegion("central-1"),
+		WithCloudProvider("GLOBEX"))
+	assert.NoError(t, err)
+	geo, err := req.ResolveGeo()
+	assert.NoError(t, err)
+	assert.Equal(t, "US", geo)
+}
+
+func TestRequest_GetElectricityMix(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           Request
+		wantGeo       string
+		expectedError string
+	}{
+		{
+			name:    "should prefer the geo over the provider region",
+			req:     Request{Geo: "FR", ProviderRegion: "us-east-1"},
+			wantGeo: "FRA",
+		},
+		{
+			name:    "should use the geo of the provider region",
+			req:     Request{ProviderRegion: "eu-west-3"},
+			wantGeo: "FRA",
+		},
+		{
+			name:    "should use the world average without geo or region",
+			req:     Request{},
+			wantGeo: WorldGeo,
+		},
+		{
+			name:          "should reject unknown regions",
+			req:           Request{ProviderRegion: "moon-south-1"},
+			expectedError: `unknown cloud region: "moon-south-1"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.req.GetElectricityMix()
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantGeo, got.Geo)
+		})
+	}
+}
+
+func TestNew_ProviderRegion(t *testing.T) {
+	req, err := New(WithOutputTokens(10), WithLatency(time.Second), WithProviderRegion(" europe-west9 "))
+	assert.NoError(t, err)
+	assert.Equal(t, "europe-west9", req.ProviderRegion)
+
+	_, err = New(WithOutputTokens(10), WithLatency(time.Second), WithProviderRegion("moon-south-1"))
+	assert.EqualError(t, err, "invalid request: ProviderRegion: must be a known cloud region (got moon-south-1)")
+
+	assert.NoError(t, RegisterCloudRegion(CloudRegion{Provider: AWS, Name: "eu-central-1", Geo: "DE"}))
+	_, err = New(WithOutputTokens(10), WithLatency(time.Second), WithProviderRegion("eu-central-1"))
+	assert.EqualError(t, err, "invalid request: ProviderRegion: has no electricity mix, load the mix of DE with "+
+		"LoadElectricityMixes (got eu-central-1)")
+}
@@ -1,23 +1,91 @@
 package request
 
+import (
+	"fmt"
+	"time"
+)
+
 type Request struct {
 	OutputTokenCount float64
-	Latency          float64
-	Geo              string
+	// InputTokenCount is the number of prompt tokens, including CachedInputTokenCount tokens served from a
+	// prompt cache.
+	InputTokenCount       float64
+	CachedInputTokenCount float64
+	// Latency is the wall-clock duration of the request, eg time.Since(start).
+	Latency time.Duration
+	// TimeToFirstToken and InterTokenLatency are measured on streamed responses and left zero otherwise.
+	// InterTokenLatency is the mean time between two consecutive output tokens.
+	TimeToFirstToken  time.Duration
+	InterTokenLatency time.Duration
+	Geo               string
+	// Timestamp is when the request was made. It is optional.
+	Timestamp time.Time
+	// ProviderRegion is the cloud region that served the request, eg "us-east-1". It is optional and locates the
+	// request when Geo is empty.
+	ProviderRegion string
+	// CloudProvider is the provider of ProviderRegion, eg AWS. It is optional unless several providers have a
+	// region with that name.
+	CloudProvider CloudProvider
+	// Tags are free-form labels, eg the team or feature that made the request, carried for reporting.
+	Tags map[string]string
+}
+
+// Streamed reports whether streaming latencies were measured for the request.
+func (r *Request) Streamed() bool {
+	return r.TimeToFirstToken > 0 || r.InterTokenLatency > 0
 }
 
+// ElectricityMix holds the impact factors of generating one kWh of electricity in a geo: ADPe in kgSbeq/kWh,
+// GWP in kgCO2eq/kWh and PE in MJ/kWh.
 type ElectricityMix struct {
-	ADPe float64
-	GWP  float64
-	PE   float64
+	// Geo is the dataset code the mix was resolved to, eg "FRA" for a request with geo "FR".
+	Geo string
+	// RequestedGeo is the normalized geo that was looked up. It differs from Geo when a region missing from the
+	// dataset fell back to its country.
+	RequestedGeo string
+	ADPe         float64
+	GWP          float64
+	PE           float64
+	// Hour is the start of the hour the factors were measured over, in UTC. It is zero for average mixes.
+	Hour time.Time
+	// Marginal reports whether GWP is a marginal intensity, ie the intensity of the plants that respond to a change
+	// in demand, rather than the average intensity of the mix.
+	Marginal bool
+	// FactorsGeo is the geo the ADPe and PE factors come from when it is not Geo, eg the world for a live carbon
+	// intensity of a geo missing from the static dataset. It is empty otherwise.
+	FactorsGeo string
+	// SourceError is the error of the live carbon intensity source the mix was requested from, when the average mix
+	// was returned instead. It is empty otherwise.
+	SourceError string
 }
 
-func (r *Request) GetElectricityMix() ElectricityMix {
-	// todo: implement a function to get the electricity mix based on the geo parameter
-	// Example: USA
-	return ElectricityMix{
-		ADPe: 0.0000000985548,
-		GWP:  0.67978,
-		PE:   11.358,
+// Fallback reports whether the mix is the one of a parent geo because the requested region is not in the dataset.
+func (m ElectricityMix) Fallback() bool {
+	return m.RequestedGeo != "" && m.RequestedGeo != m.Geo
+}
+
+// PrefillTokenCount returns the number of input tokens the model processes before generating, ie the input
+// tokens that are not served from a prompt cache.
+func (r *Request) PrefillTokenCount() (float64, error) {
+	if r.InputTokenCount < 0 {
+		return 0, fmt.Errorf("input token count cannot be negative")
+	}
+	if r.CachedInputTokenCount < 0 {
+		return 0, fmt.Errorf("cached input token count cannot be negative")
 	}
+	if r.CachedInputTokenCount > r.InputTokenCount {
+		return 0, fmt.Errorf("cached input token count (%g) exceeds input token count (%g)",
+			r.CachedInputTokenCount, r.InputTokenCount)
+	}
+	return r.InputTokenCount - r.CachedInputTokenCount, nil
+}
+
+// GetElectricityMix returns the electricity mix of the request, as Location.GetElectricityMix.
+func (r *Request) GetElectricityMix() (ElectricityMix, error) {
+	return r.location().GetElectricityMix()
+}
+
+// location returns the Location fields of the request.
+func (r *Request) location() *Location {
+	return &Location{Geo: r.Geo, Timestamp: r.Timestamp, ProviderRegion: r.ProviderRegion, CloudProvider: r.CloudProvider}
 }
@@ -0,0 +1,45 @@
+package request
+
+import "time"
+
+// TranscriptionRequest describes a speech-to-text call, which is billed by audio duration.
+type TranscriptionRequest struct {
+	// AudioDuration is the duration of the transcribed audio.
+	AudioDuration time.Duration
+	Latency       time.Duration
+	Location
+}
+
+// Validate checks every field of the request and returns ValidationErrors listing the invalid ones.
+func (r *TranscriptionRequest) Validate() error {
+	var errs ValidationErrors
+	if r.AudioDuration <= 0 {
+		errs.add("AudioDuration", r.AudioDuration, "must be greater than 0")
+	}
+	if r.Latency <= 0 {
+		errs.add("Latency", r.Latency, "must be greater than 0")
+	}
+	r.validate(&errs)
+	return errs.err()
+}
+
+// SpeechRequest describes a text-to-speech call, which is billed by input characters.
+type SpeechRequest struct {
+	// CharacterCount is the number of characters of text synthesized.
+	CharacterCount float64
+	Latency        time.Duration
+	Location
+}
+
+// Validate checks every field of the request and returns ValidationErrors listing the invalid ones.
+func (r *SpeechRequest) Validate() error {
+	var errs ValidationErrors
+	if r.CharacterCount <= 0 {
+		errs.add("CharacterCount", r.CharacterCount, "must be greater than 0")
+	}
+	if r.Latency <= 0 {
+		errs.add("Latency", r.Latency, "must be greater than 0")
+	}
+	r.validate(&errs)
+	return errs.err()
+}